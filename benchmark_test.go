@@ -209,3 +209,13 @@ func BenchmarkComplexConditionCombination(b *testing.B) {
 			Build()
 	}
 }
+
+// BenchmarkInsertStruct tests the performance of Insert query building from a tagged struct
+func BenchmarkInsertStruct(b *testing.B) {
+	user := &testUser{Name: "John", Email: "john@example.com", Age: 25}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchBuilder.InsertStruct(benchTable, user).Build()
+	}
+}
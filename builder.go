@@ -16,6 +16,8 @@ package builder
 
 import (
 	"fmt"
+	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
 )
@@ -31,16 +33,37 @@ type Builder struct {
 	sqlType SQLType
 	// dialector handles database-specific SQL syntax and escaping rules
 	dialector Dialector
-	// queryArgs holds the arguments for parameter binding in the query
-	queryArgs []interface{}
-	// query stores the current SQL query string being constructed using strings.Builder for better performance
-	query strings.Builder
+	// clauses holds the clauses of the statement being constructed, rendered in SQL order on Build
+	clauses [clauseCount]*clause
+	// current is the clause written last, to which Append adds
+	current Clause
+	// having is set while the conditions of a HAVING clause are rendered,
+	// whose fields may be aggregate calls
+	having bool
 	// setValues stores the field names being updated in an UPDATE query
 	setValues []string
+	// intoFields stores the field names of an INSERT or REPLACE query, set by Into
+	intoFields []string
+	// valuesRows counts the rows added by Values, valuesArity is the size of the first one
+	valuesRows, valuesArity int
 	// ErrList collects any errors encountered during query construction
 	ErrList []error
 	// lastQueries maintains a history of all queries built by this instance
 	lastQueries []*Query
+	// bindLimit makes LIMIT and OFFSET values be bound as query arguments
+	bindLimit bool
+	// nilAsNull makes equality conditions with a nil value render IS NULL / IS NOT NULL
+	nilAsNull bool
+	// identifierPattern validates the parts of escaped identifiers when set
+	identifierPattern *regexp.Regexp
+	// pagination records the limit and offset of the LIMIT clause
+	pagination *pagination
+	// upsert records the conflict target and update of the upsert clause
+	upsert *upsert
+	// locking records the strength, tables and wait policy of the locking clause
+	locking *locking
+	// whereRequired is recorded in ErrList on Build if the statement has no WHERE clause
+	whereRequired error
 	// The following fields are deprecated and will be removed in a future version:
 
 	// queryTables string // abandoned
@@ -51,18 +74,71 @@ type Builder struct {
 }
 
 // SetDialector sets the SQL dialect for parameter binding placeholders and identifier escaping.
+// It must be called before the statement is started: identifiers and dialect-specific clauses
+// such as LIMIT or RETURNING are rendered as they are added, so changing the dialect of a
+// builder holding clauses records ErrDialectorChanged in ErrList.
 // It returns the Builder instance for method chaining.
 func (b *Builder) SetDialector(d Dialector) *Builder {
+	if d != b.dialector {
+		for _, c := range b.clauses {
+			if c != nil {
+				b.ErrList = append(b.ErrList, ErrDialectorChanged)
+				break
+			}
+		}
+	}
 	b.dialector = d
 	return b
 }
 
 // Escape escapes the provided field names according to the current SQL dialect's rules.
+// Qualified names such as "users.id" are escaped part by part.
 // It returns the escaped string representation of the fields.
+// Names that do not match the pattern set with SetIdentifierPattern are recorded in ErrList.
 func (b *Builder) Escape(s ...string) string {
+	if b.identifierPattern != nil {
+		b.validateIdentifiers(s...)
+	}
 	return b.dialector.Escape(s...)
 }
 
+// SetIdentifierPattern sets the pattern that every part of the identifiers escaped by the
+// builder must match, e.g. DefaultIdentifierPattern, to reject identifiers coming from
+// untrusted input. A "*" part as in "users.*" is always accepted. The function name and
+// column of the aggregate calls kept unescaped in HAVING conditions are validated too.
+// Invalid identifiers are recorded in ErrList as ErrInvalidIdentifier. A nil pattern
+// disables the validation, which is the default.
+// It returns the Builder instance for method chaining.
+//
+// Example:
+//
+//	b.SetIdentifierPattern(builder.DefaultIdentifierPattern).Select("*").From("users").OrderBy(builder.Asc(sortField))
+func (b *Builder) SetIdentifierPattern(re *regexp.Regexp) *Builder {
+	b.identifierPattern = re
+	return b
+}
+
+// DefaultIdentifierPattern matches plain identifiers made of letters, digits,
+// underscores and dollar signs, not starting with a digit.
+// It can be used with Builder.SetIdentifierPattern.
+var DefaultIdentifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$]*$`)
+
+// validateIdentifiers records in ErrList the identifiers with a part
+// that does not match the identifier pattern of the builder.
+func (b *Builder) validateIdentifiers(identifiers ...string) {
+	for _, identifier := range identifiers {
+		for _, part := range strings.Split(identifier, ".") {
+			if part == "*" {
+				continue
+			}
+			if !b.identifierPattern.MatchString(part) {
+				b.ErrList = append(b.ErrList, fmt.Errorf("%w: %q", ErrInvalidIdentifier, identifier))
+				break
+			}
+		}
+	}
+}
+
 // EscapeChar returns the escape character used by the current SQL dialect
 // for escaping identifiers.
 func (b *Builder) EscapeChar() string {
@@ -72,7 +148,9 @@ func (b *Builder) EscapeChar() string {
 // Placeholder returns the appropriate parameter placeholder for the current SQL dialect
 // at the given position. For example, MySQL uses "?" while PostgreSQL uses "$1", "$2", etc.
 //
-// This method is currently disabled as the placeholder handling is done internally.
+// This method is currently disabled as the placeholder handling is done internally:
+// the builder always writes "?" and Build renumbers them through the dialect,
+// so arguments added by Append, AppendPre or Raw keep their correct ordinal.
 // func (b *Builder) Placeholder(index int) string {
 // 	return b.dialector.Placeholder(index)
 // }
@@ -84,8 +162,7 @@ func New() *Builder {
 		sqlType:   0,
 		dialector: mysqlDialector,
 		// queryTables: "",
-		queryArgs: []interface{}{},
-		query:     strings.Builder{},
+		current: headClause,
 		// from:      "",
 		setValues: []string{},
 		// where: []string{},
@@ -96,6 +173,57 @@ func New() *Builder {
 	}
 }
 
+// Clone returns a deep copy of the Builder, including the partially built query,
+// its arguments, collected errors and settings, but not the history of built queries.
+// The copy can be modified and built independently of the original, which makes it
+// possible to share a base query (e.g. an "active users" scope) and derive new
+// queries from it, including from several goroutines as long as the base itself
+// is no longer modified.
+//
+// Example:
+//
+//	active := builder.New().Select("*").From("users").Where(builder.Eq("status", "active"))
+//	admins, _ := active.Clone().And(builder.Eq("role", "admin")).Build()
+//	recent, _ := active.Clone().OrderBy(builder.Desc("created_at")).Limit(10).Build()
+func (b *Builder) Clone() *Builder {
+	c := &Builder{
+		sqlType:           b.sqlType,
+		dialector:         b.dialector,
+		current:           b.current,
+		setValues:         append([]string{}, b.setValues...),
+		intoFields:        append([]string{}, b.intoFields...),
+		valuesRows:        b.valuesRows,
+		valuesArity:       b.valuesArity,
+		ErrList:           append([]error{}, b.ErrList...),
+		lastQueries:       []*Query{},
+		bindLimit:         b.bindLimit,
+		nilAsNull:         b.nilAsNull,
+		identifierPattern: b.identifierPattern,
+		whereRequired:     b.whereRequired,
+	}
+	for i, cl := range b.clauses {
+		if cl != nil {
+			c.clauses[i] = &clause{keyword: cl.keyword, sql: cl.sql, args: append([]interface{}{}, cl.args...)}
+		}
+	}
+	if b.pagination != nil {
+		p := *b.pagination
+		c.pagination = &p
+	}
+	if b.locking != nil {
+		l := *b.locking
+		l.tables = append([]string{}, l.tables...)
+		c.locking = &l
+	}
+	if b.upsert != nil {
+		c.upsert = &upsert{
+			target: append([]string{}, b.upsert.target...),
+			update: append([]*FieldValue{}, b.upsert.update...),
+		}
+	}
+	return c
+}
+
 // LastQueries returns all previously built queries in this builder instance.
 func (b *Builder) LastQueries() []*Query {
 	return b.lastQueries
@@ -118,12 +246,14 @@ func (b *Builder) renew(st SQLType) {
 		b.ErrList = []error{}
 	}
 	b.sqlType = st
-	if len(b.queryArgs) > 0 {
-		b.queryArgs = b.queryArgs[:0]
-	} else {
-		b.queryArgs = []interface{}{}
-	}
-	b.query.Reset()
+	b.clauses = [clauseCount]*clause{}
+	b.current = headClause
+	b.pagination = nil
+	b.locking = nil
+	b.whereRequired = nil
+	b.upsert = nil
+	b.intoFields = b.intoFields[:0]
+	b.valuesRows, b.valuesArity = 0, 0
 	if len(b.setValues) > 0 {
 		b.setValues = b.setValues[:0]
 	} else {
@@ -138,6 +268,17 @@ func (b *Builder) renew(st SQLType) {
 	// b.limit = ""
 }
 
+// start begins a statement of the given type. A builder already holding a statement
+// is reset first, otherwise the clauses set so far are kept, so that e.g. From and
+// Where may be called before Select.
+func (b *Builder) start(st SQLType) {
+	if b.clauses[headClause] != nil {
+		b.renew(st)
+		return
+	}
+	b.sqlType = st
+}
+
 // Clear resets the current query and its arguments to their initial state.
 // It returns the Builder instance for method chaining.
 func (b *Builder) Clear() *Builder {
@@ -148,69 +289,194 @@ func (b *Builder) Clear() *Builder {
 // QueryArgs returns the current list of query arguments that will be used
 // for parameter binding.
 func (b *Builder) QueryArgs() []interface{} {
-	return b.queryArgs
+	_, args := b.render()
+	return args
 }
 
-// Query returns the current SQL query string being constructed.
+// Query returns the current SQL query string being constructed,
+// with placeholders rendered for the current SQL dialect.
 func (b *Builder) Query() string {
-	return b.query.String()
+	query, _ := b.render()
+	return rebind(b.dialector, query)
 }
 
-// Append adds the provided string and arguments to the end of the current query.
+// Append adds the provided string and arguments to the end of the clause written last,
+// e.g. right after the conditions of the WHERE clause when called after Where.
+// Since clauses are rendered in SQL order, ErrMisplacedAppend is recorded in ErrList
+// if a clause rendered after that one is already set, as in
+// Limit(10).Where(...).Append(" FOR UPDATE"), where the text would precede LIMIT.
 // It returns the Builder instance for method chaining.
 func (b *Builder) Append(s string, args ...interface{}) *Builder {
-	b.query.WriteString(s)
-	b.queryArgs = append(b.queryArgs, args...)
+	for later := b.current + 1; later < clauseCount; later++ {
+		if b.clauses[later] != nil {
+			b.ErrList = append(b.ErrList, ErrMisplacedAppend)
+			break
+		}
+	}
+	c := b.clause(b.current)
+	c.sql += s
+	c.args = append(c.args, args...)
 	return b
 }
 
 // AppendPre adds the provided string and arguments to the beginning of the current query.
 // It returns the Builder instance for method chaining.
 func (b *Builder) AppendPre(s string, args ...interface{}) *Builder {
-	oldQuery := b.query.String()
-	b.query.Reset()
-	b.query.WriteString(s)
-	b.query.WriteString(oldQuery)
-	b.queryArgs = append(args, b.queryArgs...)
+	if b.clauses[prefixClause] == nil {
+		b.clauses[prefixClause] = &clause{}
+	}
+	c := b.clauses[prefixClause]
+	c.sql = s + c.sql
+	c.args = append(args, c.args...)
 	return b
 }
 
 // Raw sets a raw SQL query string with optional arguments.
+// Placeholders must be written as "?"; they are renumbered for the current dialect on Build.
 // It returns the Builder instance for method chaining.
 func (b *Builder) Raw(s string, args ...interface{}) *Builder {
 	b.renew(RawSQL)
-	b.query.WriteString(s)
-	b.queryArgs = append(args, b.queryArgs...)
+	c := b.replaceClause(headClause, "")
+	c.sql = s
+	c.args = append(c.args, args...)
+	return b
+}
+
+// With adds a common table expression named name to the statement, defined by query,
+// a built *Query or an un-built *Builder, with optional column names. The statement,
+// whether a SELECT, INSERT, UPDATE or DELETE, can then refer to it as a table.
+// Calling With again adds more common table expressions, whose arguments come before
+// the ones of the statement. Note that MySQL only accepts WITH before SELECT, UPDATE
+// and DELETE statements.
+// It returns the Builder instance for method chaining.
+//
+// Example:
+//
+//	paid := builder.New().SelectExpr("user_id", builder.NewExpr("SUM(amount)").As("total")).From("orders").Where(builder.Eq("status", "paid")).GroupBy("user_id")
+//	b.With("totals", nil, paid).Select("*").From("totals").Where(builder.Gt("total", 100))
+//	// Generates: WITH `totals` AS (SELECT `user_id`, SUM(amount) AS `total` FROM `orders` WHERE `status` = ? GROUP BY `user_id`) SELECT * FROM `totals` WHERE `total` > ?
+func (b *Builder) With(name string, columns []string, query interface{}) *Builder {
+	return b.with(false, name, columns, query)
+}
+
+// WithRecursive adds a recursive common table expression like With, whose query may
+// refer to the common table expression itself, usually as the second operand of UnionAll.
+// The statement then begins with WITH RECURSIVE, which applies to all its common table expressions.
+// It returns the Builder instance for method chaining.
+//
+// Example:
+//
+//	root := builder.New().Select("id", "parent_id").From("categories").Where(builder.Eq("id", 1))
+//	children := builder.New().Select("c.id", "c.parent_id").From("categories c").Join("tree t", builder.On("c.parent_id", "=", "t.id"))
+//	b.WithRecursive("tree", []string{"id", "parent_id"}, root.UnionAll(children)).Select("id").From("tree")
+//	// Generates: WITH RECURSIVE `tree` (`id`, `parent_id`) AS (SELECT `id`, `parent_id` FROM `categories` WHERE `id` = ?
+//	//            UNION ALL SELECT `c`.`id`, `c`.`parent_id` FROM `categories` AS `c` INNER JOIN `tree` AS `t` ON `c`.`parent_id` = `t`.`id`) SELECT `id` FROM `tree`
+func (b *Builder) WithRecursive(name string, columns []string, query interface{}) *Builder {
+	return b.with(true, name, columns, query)
+}
+
+// with adds a common table expression to the WITH clause of the statement.
+// Errors are collected in the Builder's ErrList.
+func (b *Builder) with(recursive bool, name string, columns []string, query interface{}) *Builder {
+	sub, args, err := b.buildSubquery(query)
+	if err != nil {
+		b.ErrList = append(b.ErrList, err)
+		return b
+	}
+
+	c := b.clause(WithClause)
+	if c.sql == "" {
+		c.keyword = "WITH "
+	} else {
+		// Replace the space separating the clause from the statement.
+		c.sql = c.sql[:len(c.sql)-1] + ", "
+	}
+	if recursive {
+		c.keyword = "WITH RECURSIVE "
+	}
+	c.sql += b.Escape(name)
+	if len(columns) > 0 {
+		c.sql += " (" + b.Escape(columns...) + ")"
+	}
+	c.sql += " AS (" + sub + ") "
+	c.args = append(c.args, args...)
 	return b
 }
 
 // Select begins a SELECT query with the specified fields.
 // If no fields are provided, it creates an empty SELECT.
 // If "*" is provided as the first field, it selects all columns.
+// Fields may be qualified (e.g. "u.name") and may carry an alias (e.g. "u.name AS author"),
+// they are always escaped as identifiers. Use SelectExpr for expressions such as "COUNT(*)".
 // It returns the Builder instance for method chaining.
 func (b *Builder) Select(fields ...string) *Builder {
-	b.renew(SelectSQL)
-	b.query.WriteString("SELECT")
+	b.start(SelectSQL)
+	c := b.replaceClause(headClause, "SELECT")
 
 	if len(fields) <= 0 {
 		// Do nothing
 	} else if fields[0] == "*" {
-		b.query.WriteString(" *")
+		c.sql = " *"
 	} else {
-		b.query.WriteString(" ")
-		b.query.WriteString(b.Escape(fields...))
+		escaped := make([]string, len(fields))
+		for i, field := range fields {
+			escaped[i] = b.escapeColumn(field)
+		}
+		c.sql = " " + strings.Join(escaped, ", ")
 		// b.query += " `" + strings.Join(fields, "`, `") + "`"
 	}
 
 	return b
 }
 
+// SelectExpr begins a SELECT query with the specified columns, which can be field names
+// (handled as in Select), Expr values rendered as is with their arguments and optional alias,
+// sub-queries, Column references or any other value, which is bound as a parameter.
+// It returns the Builder instance for method chaining.
+//
+// Example:
+//
+//	b.SelectExpr("users.name AS author", builder.NewExpr("COUNT(*)").As("posts"), builder.NewExpr("AVG(score) * ?", 2)).
+//	  From("users")
+//	// Generates: SELECT `users`.`name` AS `author`, COUNT(*) AS `posts`, AVG(score) * ? FROM `users`
+func (b *Builder) SelectExpr(columns ...interface{}) *Builder {
+	b.start(SelectSQL)
+	c := b.replaceClause(headClause, "SELECT")
+
+	parts := make([]string, 0, len(columns))
+	for _, column := range columns {
+		var (
+			part string
+			args []interface{}
+			err  error
+		)
+		switch column := column.(type) {
+		case string:
+			part = b.escapeColumn(column)
+		default:
+			if part, args, err = b.buildValue(column); err != nil {
+				b.ErrList = append(b.ErrList, err)
+				part = fmt.Sprintf("{error: %s}", err)
+			}
+			if e, ok := column.(Expr); ok && e.Alias != "" {
+				part += " AS " + b.Escape(e.Alias)
+			}
+		}
+		parts = append(parts, part)
+		c.args = append(c.args, args...)
+	}
+	if len(parts) > 0 {
+		c.sql = " " + strings.Join(parts, ", ")
+	}
+
+	return b
+}
+
 // Insert begins an INSERT query for the specified table and optional field names.
 // It returns the Builder instance for method chaining.
 func (b *Builder) Insert(tableName string, fields ...string) *Builder {
-	b.renew(InsertSQL)
-	b.query.WriteString("INSERT INTO ")
-	b.query.WriteString(b.Escape(tableName))
+	b.start(InsertSQL)
+	b.replaceClause(headClause, "INSERT INTO ").sql = b.Escape(tableName)
 
 	if len(fields) > 0 {
 		b.Into(fields...)
@@ -220,11 +486,11 @@ func (b *Builder) Insert(tableName string, fields ...string) *Builder {
 
 // InsertOrUpdate begins an INSERT ... ON DUPLICATE KEY UPDATE query for MySQL.
 // It takes field-value pairs that will be used for both the INSERT and UPDATE parts.
+// PostgreSQL and SQLite need a conflict target, use Insert with OnConflict and DoUpdate instead.
 // It returns the Builder instance for method chaining.
 func (b *Builder) InsertOrUpdate(tableName string, fvals ...*FieldValue) *Builder {
-	b.renew(InsertSQL)
-	b.query.WriteString("INSERT INTO ")
-	b.query.WriteString(b.Escape(tableName))
+	b.start(InsertSQL)
+	b.replaceClause(headClause, "INSERT INTO ").sql = b.Escape(tableName)
 
 	if len(fvals) > 0 {
 		var (
@@ -237,18 +503,133 @@ func (b *Builder) InsertOrUpdate(tableName string, fvals ...*FieldValue) *Builde
 				vals = append(vals, fv.Value)
 			}
 		}
-		b.Into(fields...).Values(vals).Append(" ON DUPLICATE KEY UPDATE ").Set(fvals...)
+		b.Into(fields...).Values(vals).DoUpdate(fvals...)
+	}
+
+	return b
+}
+
+// OnConflict sets the conflict target columns of an upsert, completed by DoUpdate,
+// DoUpdateColumns or DoNothing, before or after it. The target is required by PostgreSQL
+// and SQLite to update conflicting rows, and is ignored by MySQL except for DoNothing.
+// It returns the Builder instance for method chaining.
+//
+// Example:
+//
+//	b.Insert("users", "id", "name").
+//	  Values([]interface{}{1, "coder"}, []interface{}{2, "hacker"}).
+//	  OnConflict("id").
+//	  DoUpdateColumns("name")
+//	// PostgreSQL: INSERT INTO "users" ("id", "name") VALUES ($1, $2), ($3, $4)
+//	//             ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name"
+//	// MySQL:      INSERT INTO `users` (`id`, `name`) VALUES (?, ?), (?, ?)
+//	//             ON DUPLICATE KEY UPDATE `name` = VALUES(`name`)
+func (b *Builder) OnConflict(columns ...string) *Builder {
+	if b.upsert == nil {
+		b.upsert = &upsert{}
+	}
+	b.upsert.target = append([]string{}, columns...)
+	return b
+}
+
+// DoUpdate adds the upsert clause, updating conflicting rows with the given field-value
+// pairs. Use an Excluded value to refer to the value proposed for insertion.
+// The clause is rendered by the SQL dialect of the builder on Build, and errors
+// such as a missing conflict target are recorded then.
+// It returns the Builder instance for method chaining.
+//
+// Example:
+//
+//	b.Insert("counters", "name", "hits").Values([]interface{}{"home", 1}).
+//	  OnConflict("name").
+//	  DoUpdate(builder.NewFV("hits", builder.Excluded("hits")), builder.NewFV("updated_by", "job"))
+func (b *Builder) DoUpdate(fvals ...*FieldValue) *Builder {
+	update := make([]*FieldValue, 0, len(fvals))
+	for _, fval := range fvals {
+		if fval != nil {
+			update = append(update, fval)
+		}
+	}
+	if len(update) <= 0 {
+		return b.DoNothing()
+	}
+	return b.setUpsert(update)
+}
+
+// DoUpdateColumns adds the upsert clause of the current SQL dialect, updating the given
+// columns of conflicting rows with the values proposed for insertion.
+// It is a shorthand for DoUpdate with Excluded values.
+// It returns the Builder instance for method chaining.
+func (b *Builder) DoUpdateColumns(columns ...string) *Builder {
+	fvals := make([]*FieldValue, 0, len(columns))
+	for _, column := range columns {
+		fvals = append(fvals, NewFieldValue(column, Excluded(column)))
 	}
+	return b.DoUpdate(fvals...)
+}
+
+// DoNothing adds the upsert clause that leaves conflicting rows untouched, rendered
+// on Build like DoUpdate. MySQL has no DO NOTHING, so it is rendered as a no-op
+// update of the first OnConflict column, which is therefore required.
+// It returns the Builder instance for method chaining.
+func (b *Builder) DoNothing() *Builder {
+	return b.setUpsert(nil)
+}
 
+// upsert records the conflict target and the update of an upsert, so that the
+// clause is rendered on Build whatever the order of OnConflict, DoUpdate and SetDialector.
+type upsert struct {
+	target []string      // unescaped conflict target columns
+	update []*FieldValue // assignments of DoUpdate, none for DoNothing
+}
+
+// setUpsert sets the update of the upsert clause, which is left empty until buildUpsert.
+func (b *Builder) setUpsert(update []*FieldValue) *Builder {
+	if b.upsert == nil {
+		b.upsert = &upsert{}
+	}
+	b.upsert.update = update
+	b.replaceClause(UpsertClause, " ")
 	return b
 }
 
+// buildUpsert renders the upsert clause with the current SQL dialect, before any text
+// added to it by Append. Errors are collected in the Builder's ErrList.
+func (b *Builder) buildUpsert() {
+	c := b.clauses[UpsertClause]
+	if b.upsert == nil || c == nil {
+		return
+	}
+	u := b.upsert
+	b.upsert = nil
+
+	target := make([]string, len(u.target))
+	for i, column := range u.target {
+		target[i] = b.Escape(column)
+	}
+	var (
+		set  = make([]string, 0, len(u.update))
+		args []interface{}
+	)
+	for _, fval := range u.update {
+		assignment, fvalArgs := b.buildAssignment(fval)
+		set = append(set, assignment)
+		args = append(args, fvalArgs...)
+	}
+	clause, err := b.dialector.Upsert(target, strings.Join(set, ", "))
+	if err != nil {
+		b.ErrList = append(b.ErrList, err)
+		return
+	}
+	c.sql = clause + c.sql
+	c.args = append(args, c.args...)
+}
+
 // Replace begins a REPLACE query for the specified table and optional field names.
 // It returns the Builder instance for method chaining.
 func (b *Builder) Replace(tableName string, fields ...string) *Builder {
-	b.renew(InsertSQL)
-	b.query.WriteString("REPLACE INTO ")
-	b.query.WriteString(b.Escape(tableName))
+	b.start(InsertSQL)
+	b.replaceClause(headClause, "REPLACE INTO ").sql = b.Escape(tableName)
 
 	if len(fields) > 0 {
 		b.Into(fields...)
@@ -259,9 +640,8 @@ func (b *Builder) Replace(tableName string, fields ...string) *Builder {
 // Into specifies the fields for an INSERT or REPLACE query.
 // It returns the Builder instance for method chaining.
 func (b *Builder) Into(fields ...string) *Builder {
-	b.query.WriteString(" (")
-	b.query.WriteString(b.Escape(fields...))
-	b.query.WriteString(")")
+	b.intoFields = append(b.intoFields[:0], fields...)
+	b.replaceClause(IntoClause, " ").sql = "(" + b.Escape(fields...) + ")"
 	// b.query += " (`" + strings.Join(fields, "`, `") + "`)"
 	return b
 }
@@ -277,14 +657,28 @@ var __placeholders = []string{
 }
 
 // Values adds one or more sets of values to an INSERT or REPLACE query.
-// Each set of values must match the number of fields specified in Into().
+// Each set of values must match the number of fields specified in Into(), or the
+// number of values of the first row if there are none. An empty or mismatched row
+// is left out and a *ValuesError is recorded in ErrList.
+// A Default value is rendered as the DEFAULT keyword instead of a placeholder.
+// Calling Values again adds more rows to the VALUES list, replacing a row source
+// set by FromSelect or DefaultValues.
 // It returns the Builder instance for method chaining.
 func (b *Builder) Values(valsGroup ...[]interface{}) *Builder {
-	b.query.WriteString(" VALUES ")
+	c := b.clause(ValuesClause)
+	if c.keyword != " VALUES " {
+		c = b.replaceClause(ValuesClause, " VALUES ")
+		b.valuesRows, b.valuesArity = 0, 0
+	}
+	var sb strings.Builder
 	// index := 0
-	for i, vals := range valsGroup {
-		if i > 0 {
-			b.query.WriteString(", ")
+	for _, vals := range valsGroup {
+		if err := b.checkRow(vals); err != nil {
+			b.ErrList = append(b.ErrList, err)
+			continue
+		}
+		if sb.Len() > 0 || c.sql != "" {
+			sb.WriteString(", ")
 		}
 		// b.query += "("
 		// for j, val := range vals {
@@ -297,26 +691,125 @@ func (b *Builder) Values(valsGroup ...[]interface{}) *Builder {
 		// }
 		// b.query += ")"
 
+		if hasDefault(vals) {
+			row, args := b.buildRow(vals)
+			sb.WriteString(row)
+			c.args = append(c.args, args...)
+			continue
+		}
 		// Use the predefined placeholder string when there are less than 6 values.
 		if len(vals) > 5 {
-			b.query.WriteString("(?")
-			b.query.WriteString(strings.Repeat(", ?", len(vals)-1))
-			b.query.WriteString(")")
+			sb.WriteString("(?")
+			sb.WriteString(strings.Repeat(", ?", len(vals)-1))
+			sb.WriteString(")")
 		} else {
-			b.query.WriteString(__placeholders[len(vals)-1])
+			sb.WriteString(__placeholders[len(vals)-1])
 		}
-		b.queryArgs = append(b.queryArgs, vals...)
+		c.args = append(c.args, vals...)
 	}
+	c.sql += sb.String()
+	return b
+}
+
+// checkRow counts a row of values added by Values and returns a *ValuesError
+// if it is empty or does not match the fields set by Into, or the first row.
+func (b *Builder) checkRow(vals []interface{}) error {
+	row := b.valuesRows
+	b.valuesRows++
+
+	want := len(b.intoFields)
+	if want == 0 {
+		if b.valuesArity == 0 {
+			b.valuesArity = len(vals)
+		}
+		want = b.valuesArity
+	}
+	if len(vals) == 0 || len(vals) != want {
+		return &ValuesError{Row: row, Got: len(vals), Want: want}
+	}
+	return nil
+}
+
+// hasDefault reports whether a row of values contains a Default value.
+func hasDefault(vals []interface{}) bool {
+	for _, val := range vals {
+		if _, ok := val.(defaultValue); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// buildRow renders a row of values in parentheses along with its arguments,
+// with the DEFAULT keyword of the dialect for Default values.
+// Errors are collected in the Builder's ErrList.
+func (b *Builder) buildRow(vals []interface{}) (string, []interface{}) {
+	var (
+		placeholders = make([]string, len(vals))
+		args         []interface{}
+	)
+	for i, val := range vals {
+		if _, ok := val.(defaultValue); !ok {
+			placeholders[i] = "?"
+			args = append(args, val)
+			continue
+		}
+		keyword, err := b.dialector.DefaultValue()
+		if err != nil {
+			b.ErrList = append(b.ErrList, err)
+			keyword = fmt.Sprintf("{error: %s}", err)
+		}
+		placeholders[i] = keyword
+	}
+	return "(" + strings.Join(placeholders, ", ") + ")", args
+}
+
+// FromSelect sets the rows of an INSERT or REPLACE query to the result of the given
+// query, a built *Query or an un-built *Builder, whose columns must match the fields
+// of the INSERT. It replaces any row set by Values.
+// It returns the Builder instance for method chaining.
+//
+// Example:
+//
+//	old := builder.New().Select("id", "name").From("users").Where(builder.Lt("last_login", "2020-01-01"))
+//	b.Insert("archive", "id", "name").FromSelect(old)
+//	// Generates: INSERT INTO `archive` (`id`, `name`) SELECT `id`, `name` FROM `users` WHERE `last_login` < ?
+func (b *Builder) FromSelect(query interface{}) *Builder {
+	sub, args, err := b.buildSubquery(query)
+	if err != nil {
+		b.ErrList = append(b.ErrList, err)
+		return b
+	}
+	c := b.replaceClause(ValuesClause, " ")
+	c.sql = sub
+	c.args = args
+	b.valuesRows, b.valuesArity = 0, 0
+	return b
+}
+
+// DefaultValues makes an INSERT query insert a single row made of default values,
+// rendered by the current SQL dialect, e.g. "DEFAULT VALUES" for PostgreSQL and SQLite
+// and "() VALUES ()" for MySQL. It removes the fields set by Into and any row set by Values.
+// It returns the Builder instance for method chaining.
+//
+// Example:
+//
+//	b.Insert("events").DefaultValues().Returning("id")
+//	// Generates: INSERT INTO "events" DEFAULT VALUES RETURNING "id"
+func (b *Builder) DefaultValues() *Builder {
+	b.RemoveClause(IntoClause)
+	b.replaceClause(ValuesClause, " ").sql = b.dialector.DefaultValues()
+	b.valuesRows, b.valuesArity = 0, 0
 	return b
 }
 
 // Update begins an UPDATE query for the specified table with optional field-value pairs.
+// The table may carry an alias, written as "users AS u" or "users u".
 // It returns the Builder instance for method chaining.
 func (b *Builder) Update(tableName string, fvals ...*FieldValue) *Builder {
-	b.renew(UpdateSQL)
-	b.query.WriteString("UPDATE ")
-	b.query.WriteString(b.Escape(tableName))
-	b.query.WriteString(" SET ")
+	b.start(UpdateSQL)
+	b.replaceClause(headClause, "UPDATE ").sql = b.escapeTable(tableName)
+	b.clause(SetClause).keyword = " SET "
 
 	if len(fvals) > 0 {
 		b.Set(fvals...)
@@ -326,38 +819,178 @@ func (b *Builder) Update(tableName string, fvals ...*FieldValue) *Builder {
 }
 
 // Set specifies the field-value pairs to update in an UPDATE query.
+// Calling Set again adds more pairs to the SET list.
+// In an INSERT query, the pairs are added to the clause written last instead, e.g. after
+// a raw " ON DUPLICATE KEY UPDATE " added by Append; DoUpdate is the portable way.
 // It returns the Builder instance for method chaining.
 func (b *Builder) Set(fvals ...*FieldValue) *Builder {
 	// b.setValue = ""
+	var (
+		c         *clause
+		separated bool
+	)
+	if b.sqlType == InsertSQL {
+		c = b.clause(b.current)
+		separated = len(b.setValues) > 0
+	} else {
+		c = b.clause(SetClause)
+		c.keyword = " SET "
+		separated = c.sql != ""
+	}
 
-	for i, fval := range fvals {
+	for _, fval := range fvals {
 		if fval == nil {
 			continue
 		}
-		if i > 0 || len(b.setValues) > 0 {
-			b.query.WriteString(", ")
+		if separated {
+			c.sql += ", "
 		}
+		separated = true
 		b.setValues = append(b.setValues, fval.Name)
-		b.query.WriteString(b.Escape(fval.Name))
-		b.query.WriteString(" = ?")
-		b.queryArgs = append(b.queryArgs, fval.Value)
+		assignment, args := b.buildAssignment(fval)
+		c.sql += assignment
+		c.args = append(c.args, args...)
 	}
 
 	return b
 }
 
+// buildAssignment renders a "field = value" pair of a SET list along with its arguments.
+// The value is rendered by buildValue, so it can also be a Column, an Excluded
+// reference or a sub-query. Errors are collected in the Builder's ErrList.
+func (b *Builder) buildAssignment(fval *FieldValue) (string, []interface{}) {
+	value, args, err := b.buildValue(fval.Value)
+	if err != nil {
+		b.ErrList = append(b.ErrList, err)
+		value = fmt.Sprintf("{error: %s}", err)
+	}
+	return b.Escape(fval.Name) + " = " + value, args
+}
+
 // Delete begins a DELETE query for the specified table.
+// The table may carry an alias, written as "users AS u" or "users u".
 // It returns the Builder instance for method chaining.
 func (b *Builder) Delete(tableName string) *Builder {
-	b.renew(DeleteSQL)
-	b.query.WriteString("DELETE FROM ")
-	b.query.WriteString(b.Escape(tableName))
+	b.start(DeleteSQL)
+	b.replaceClause(headClause, "DELETE FROM ").sql = b.escapeTable(tableName)
+
+	return b
+}
+
+// Returning adds a RETURNING clause with the specified fields to an INSERT, UPDATE
+// or DELETE query (including upserts), so that the affected rows are returned.
+// If "*" is provided as the first field, all columns are returned.
+// An error is recorded in ErrList when the current SQL dialect does not support
+// RETURNING (e.g. MySQL) or the query is not an INSERT, UPDATE or DELETE.
+// It returns the Builder instance for method chaining.
+//
+// Example:
+//
+//	b.Insert("users", "name").Values([]interface{}{"coder"}).Returning("id", "created_at")
+//	// Generates: INSERT INTO "users" ("name") VALUES ($1) RETURNING "id", "created_at"
+func (b *Builder) Returning(fields ...string) *Builder {
+	switch b.sqlType {
+	case InsertSQL, UpdateSQL, DeleteSQL:
+	default:
+		b.ErrList = append(b.ErrList, fmt.Errorf("RETURNING is only valid for INSERT, UPDATE or DELETE queries (sql type: %d)", b.sqlType))
+		return b
+	}
+	if len(fields) <= 0 {
+		return b
+	}
+
+	columns := "*"
+	if fields[0] != "*" {
+		escaped := make([]string, len(fields))
+		for i, field := range fields {
+			escaped[i] = b.Escape(field)
+		}
+		columns = strings.Join(escaped, ", ")
+	}
+	clause, err := b.dialector.Returning(columns)
+	if err != nil {
+		b.ErrList = append(b.ErrList, err)
+		return b
+	}
+	b.replaceClause(ReturningClause, " ").sql = clause
+	return b
+}
+
+// Union combines the SELECT query with the given queries, each a built *Query or an
+// un-built *Builder, keeping distinct rows only. The ORDER BY and LIMIT clauses of
+// the Builder apply to the combined result, and the arguments of the queries are
+// added in order. A *Builder with its own ORDER BY, LIMIT or set operations is put
+// in parentheses, which SQLite does not accept.
+// It returns the Builder instance for method chaining.
+//
+// Example:
+//
+//	archived := builder.New().Select("id", "name").From("archived_users").Where(builder.Eq("status", 1))
+//	b.Select("id", "name").From("users").Where(builder.Eq("status", 1)).Union(archived).OrderBy(builder.Asc("name")).Limit(10)
+//	// Generates: SELECT `id`, `name` FROM `users` WHERE `status` = ? UNION SELECT `id`, `name` FROM `archived_users` WHERE `status` = ? ORDER BY `name` ASC LIMIT 10
+func (b *Builder) Union(queries ...interface{}) *Builder {
+	return b.setOperation("UNION", queries)
+}
+
+// UnionAll combines the SELECT query with the given queries like Union, keeping duplicate rows.
+// It returns the Builder instance for method chaining.
+func (b *Builder) UnionAll(queries ...interface{}) *Builder {
+	return b.setOperation("UNION ALL", queries)
+}
+
+// Intersect combines the SELECT query with the given queries like Union, keeping the rows
+// returned by all of them. An error is recorded in ErrList when the current SQL dialect
+// does not support INTERSECT (e.g. MySQL before 8.0.31).
+// It returns the Builder instance for method chaining.
+func (b *Builder) Intersect(queries ...interface{}) *Builder {
+	return b.setOperation("INTERSECT", queries)
+}
+
+// Except combines the SELECT query with the given queries like Union, keeping the rows
+// not returned by any of them. An error is recorded in ErrList when the current SQL
+// dialect does not support EXCEPT (e.g. MySQL before 8.0.31).
+// It returns the Builder instance for method chaining.
+func (b *Builder) Except(queries ...interface{}) *Builder {
+	return b.setOperation("EXCEPT", queries)
+}
 
+// setOperation adds the given queries to the set operations of the query, combined with
+// the given operator as rendered by the dialect. Errors are collected in the Builder's ErrList.
+func (b *Builder) setOperation(operator string, queries []interface{}) *Builder {
+	switch b.sqlType {
+	case InsertSQL, ReplaceSQL, UpdateSQL, DeleteSQL:
+		b.ErrList = append(b.ErrList, fmt.Errorf("%s is only valid for SELECT queries (sql type: %d)", operator, b.sqlType))
+		return b
+	}
+	if len(queries) <= 0 {
+		return b
+	}
+	keyword, err := b.dialector.SetOperation(operator)
+	if err != nil {
+		b.ErrList = append(b.ErrList, err)
+		return b
+	}
+
+	c := b.clause(CompoundClause)
+	for _, q := range queries {
+		query, args, err := b.buildSubquery(q)
+		if err != nil {
+			b.ErrList = append(b.ErrList, err)
+			continue
+		}
+		if sub, ok := q.(*Builder); ok && (sub.HasClause(CompoundClause) || sub.HasClause(OrderByClause) || sub.HasClause(LimitClause)) {
+			query = "(" + query + ")"
+		}
+		c.sql += " " + keyword + " " + query
+		c.args = append(c.args, args...)
+	}
 	return b
 }
 
 // Build finalizes the query construction and returns a Query object along with any errors.
 // It validates the SQL type and any accumulated errors before creating the final query.
+// The clauses are joined in SQL order, whatever the order in which they were set,
+// and placeholders are rendered for the current SQL dialect, e.g. "$1, $2" for PostgreSQL.
 func (b *Builder) Build(queries ...interface{}) (q *Query, err error) {
 
 	switch b.sqlType {
@@ -370,59 +1003,232 @@ func (b *Builder) Build(queries ...interface{}) (q *Query, err error) {
 	default:
 		return nil, ErrEmptySQLType
 	}
+	if b.whereRequired != nil && !b.HasClause(WhereClause) {
+		b.ErrList = append(b.ErrList, b.whereRequired)
+	}
+	b.buildUpsert()
 	if len(b.ErrList) > 0 {
 		err = ErrListIsNotEmpty
 	}
-	q = NewQuery(b.query.String(), b.queryArgs...)
+	query, args := b.render()
+	q = NewQuery(rebind(b.dialector, query), args...)
 	b.lastQueries = append(b.lastQueries, q)
 	b.renew(RawSQL)
 	return q, err
 }
 
+// BuildBatch builds an INSERT or REPLACE query for each chunk of the given rows, so that
+// no query has more bound parameters than the current SQL dialect accepts (see
+// Dialector.MaxParameters). The other clauses of the query, e.g. an upsert or a
+// RETURNING clause, are repeated in every query, and any row set by Values is replaced.
+// Every row must have as many values as the fields set by Into, or as the first row
+// if there are none, otherwise a *ValuesError is returned.
+// Like Build, it resets the Builder. Use Executor.ExecBatch to run the queries in a
+// single transaction.
+//
+// Example:
+//
+//	queries, err := b.Insert("users", "name", "age").BuildBatch(rows...)
+//	// With 100000 rows on MySQL: 4 queries, 3 of 32767 rows and 1 of 1699 rows
+func (b *Builder) BuildBatch(rows ...[]interface{}) (queries []*Query, err error) {
+	defer b.renew(RawSQL)
+
+	if b.sqlType != InsertSQL {
+		return nil, fmt.Errorf("BuildBatch is only valid for INSERT or REPLACE queries (sql type: %d)", b.sqlType)
+	}
+	if len(b.ErrList) > 0 {
+		return nil, fmt.Errorf("%w: %v", ErrListIsNotEmpty, b.ErrList)
+	}
+	arity := len(b.intoFields)
+	for i, row := range rows {
+		if arity == 0 {
+			arity = len(row)
+		}
+		if len(row) == 0 || len(row) != arity {
+			return nil, &ValuesError{Row: i, Got: len(row), Want: arity}
+		}
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	template := b.Clone().RemoveClause(ValuesClause)
+	if template.buildUpsert(); len(template.ErrList) > 0 {
+		return nil, fmt.Errorf("%w: %v", ErrListIsNotEmpty, template.ErrList)
+	}
+	size := len(rows)
+	if max := b.dialector.MaxParameters(); max > 0 {
+		_, args := template.render()
+		if size = (max - len(args)) / arity; size < 1 {
+			return nil, fmt.Errorf("a row of %d values exceeds the limit of %d parameters", arity, max)
+		}
+	}
+	for start := 0; start < len(rows); start += size {
+		end := start + size
+		if end > len(rows) {
+			end = len(rows)
+		}
+		chunk := template.Clone().Values(rows[start:end]...)
+		errs := append([]error{}, chunk.ErrList...)
+		q, err := chunk.Build()
+		if err == ErrListIsNotEmpty {
+			return nil, fmt.Errorf("%w: %v", err, errs)
+		} else if err != nil {
+			return nil, err
+		}
+		queries = append(queries, q)
+	}
+	b.lastQueries = append(b.lastQueries, queries...)
+	return queries, nil
+}
+
 // From specifies the tables to select from in a SELECT query.
+// Each table may be qualified and may carry an alias, written as "users AS u" or "users u".
 // It returns the Builder instance for method chaining.
+//
+// Example:
+//
+//	b.Select("u.id AS user_id", "p.title").From("users AS u", "posts p")
+//	// Generates: SELECT `u`.`id` AS `user_id`, `p`.`title` FROM `users` AS `u`, `posts` AS `p`
 func (b *Builder) From(tables ...string) *Builder {
 	if len(tables) <= 0 {
 		return b
 	}
 	// b.Tables = tables
 	// b.QueryTables = "`" + strings.Join(tables, "`, `") + "`"
-	b.query.WriteString(" FROM ")
-	b.query.WriteString(b.Escape(tables...))
+	escaped := make([]string, len(tables))
+	for i, table := range tables {
+		escaped[i] = b.escapeTable(table)
+	}
+	b.replaceClause(FromClause, " FROM ").sql = strings.Join(escaped, ", ")
 	// b.query += " FROM `" + strings.Join(tables, "`, `") + "`"
 	return b
 }
 
+// FromSub specifies a sub-query as the source of a SELECT query, given as a built
+// *Query or an un-built *Builder, with an optional alias for the derived table.
+// The arguments of the sub-query are added to the query in order.
+// It returns the Builder instance for method chaining.
+//
+// Example:
+//
+//	sub := builder.New().Select("user_id").From("orders").Where(builder.Gt("amount", 100))
+//	b.Select("*").FromSub(sub, "t")
+//	// Generates: SELECT * FROM (SELECT `user_id` FROM `orders` WHERE `amount` > ?) AS `t`
+func (b *Builder) FromSub(sub interface{}, alias string) *Builder {
+	query, args, err := b.buildSubquery(sub)
+	if err != nil {
+		b.ErrList = append(b.ErrList, err)
+		return b
+	}
+	c := b.replaceClause(FromClause, " FROM ")
+	c.sql = "(" + query + ")"
+	if alias != "" {
+		c.sql += " AS " + b.Escape(alias)
+	}
+	c.args = append(c.args, args...)
+	return b
+}
+
 // FromRaw specifies a raw FROM clause without any escaping.
 // It returns the Builder instance for method chaining.
 func (b *Builder) FromRaw(from string) *Builder {
-	b.query.WriteString(" FROM ")
-	b.query.WriteString(from)
+	b.replaceClause(FromClause, " FROM ").sql = from
+	return b
+}
+
+// Join adds an INNER JOIN clause for the given table, which may carry an alias
+// (e.g. "orders AS o" or "orders o"). The conditions are rendered as the ON clause
+// and combined the same way as in Where; use On to compare two columns.
+// If no conditions are provided, the ON clause is omitted so that Using can follow.
+// It returns the Builder instance for method chaining.
+//
+// Example:
+//
+//	b.Select("*").From("users").
+//	  Join("orders o", builder.On("users.id", "=", "o.user_id"), builder.And("o.status", "=", "paid"))
+//	// Generates: SELECT * FROM `users` INNER JOIN `orders` AS `o` ON `users`.`id` = `o`.`user_id` AND `o`.`status` = ?
+func (b *Builder) Join(table string, conditions ...*Condition) *Builder {
+	return b.join("INNER JOIN", table, conditions...)
+}
+
+// LeftJoin adds a LEFT JOIN clause for the given table. See Join for details.
+// It returns the Builder instance for method chaining.
+func (b *Builder) LeftJoin(table string, conditions ...*Condition) *Builder {
+	return b.join("LEFT JOIN", table, conditions...)
+}
+
+// RightJoin adds a RIGHT JOIN clause for the given table. See Join for details.
+// It returns the Builder instance for method chaining.
+func (b *Builder) RightJoin(table string, conditions ...*Condition) *Builder {
+	return b.join("RIGHT JOIN", table, conditions...)
+}
+
+// FullJoin adds a FULL JOIN clause for the given table. See Join for details.
+// Note that MySQL does not support FULL JOIN.
+// It returns the Builder instance for method chaining.
+func (b *Builder) FullJoin(table string, conditions ...*Condition) *Builder {
+	return b.join("FULL JOIN", table, conditions...)
+}
+
+// CrossJoin adds a CROSS JOIN clause for the given table.
+// It returns the Builder instance for method chaining.
+func (b *Builder) CrossJoin(table string) *Builder {
+	return b.join("CROSS JOIN", table)
+}
+
+// Using adds a USING clause with the given columns to the preceding join.
+// It returns the Builder instance for method chaining.
+//
+// Example:
+//
+//	b.Select("*").From("users").Join("profiles").Using("user_id")
+//	// Generates: SELECT * FROM `users` INNER JOIN `profiles` USING (`user_id`)
+func (b *Builder) Using(columns ...string) *Builder {
+	if len(columns) <= 0 {
+		return b
+	}
+	b.clause(JoinClause).sql += " USING (" + b.Escape(columns...) + ")"
+	return b
+}
+
+// join adds a join of the given kind along with its ON conditions to the joins of the query.
+func (b *Builder) join(kind string, table string, conditions ...*Condition) *Builder {
+	c := b.clause(JoinClause)
+	c.sql += " " + kind + " " + b.escapeTable(table)
+
+	if len(conditions) > 0 {
+		on, args := b.buildConditions(conditions...)
+		c.sql += " ON " + on
+		c.args = append(c.args, args...)
+	}
 	return b
 }
 
 // Where begins the WHERE clause of a query with the specified conditions.
 // If no conditions are provided, it adds "WHERE 1".
 // It returns the Builder instance for method chaining.
-// addConditions adds one or more conditions to the query.
+// buildConditions builds one or more conditions along with their arguments.
 // It handles the AND/OR logic between conditions and builds each condition
 // using buildCondition. Any errors encountered during condition building
 // are collected in the Builder's ErrList.
 //
 // Parameters:
-//   - conditions: Variable number of Condition objects to be added
+//   - conditions: Variable number of Condition objects to be built
 //
 // Returns:
-//   - *Builder: The Builder instance for method chaining
+//   - string: The conditions joined by their AND/OR logic
+//   - []interface{}: The arguments of the conditions
 //
 // Example:
 //
-//	b.addConditions(
+//	b.buildConditions(
 //	  builder.Eq("status", "active"),
 //	  builder.Gt("age", 18)
 //	)
 //	// Generates: `status` = ? AND `age` > ?
-func (b *Builder) addConditions(conditions ...*Condition) *Builder {
+func (b *Builder) buildConditions(conditions ...*Condition) (string, []interface{}) {
+	var queryArgs []interface{}
 	condSlice := make([]string, 0, len(conditions))
 	for i, cond := range conditions {
 		if cond == nil {
@@ -439,34 +1245,51 @@ func (b *Builder) addConditions(conditions ...*Condition) *Builder {
 			condStr = "OR " + condStr
 		}
 		condSlice = append(condSlice, condStr)
-		b.queryArgs = append(b.queryArgs, args...)
+		queryArgs = append(queryArgs, args...)
 	}
 	// b.where = append(b.where, "("+strings.Join(condSlice, " ")+")")
 	// if len(conditions) <= 0 {
 	// 	condSlice = append(condSlice, "1")
 	// }
 	// b.where = append(b.where, strings.Join(condSlice, " "))
-	b.query.WriteString(strings.Join(condSlice, " "))
-	return b
+	return strings.Join(condSlice, " "), queryArgs
+}
 
+// where adds conditions to the WHERE clause, which is started if it is empty.
+// Otherwise the conditions are preceded by the given conjunction (" AND " or " OR "),
+// unless the clause already ends with one added by Append, and grouped in parentheses
+// when more than one condition is provided.
+func (b *Builder) where(conj string, conditions ...*Condition) *Builder {
+	str, args := b.buildConditions(conditions...)
+	c := b.clause(WhereClause)
+	if end := strings.ToUpper(strings.TrimRight(c.sql, " ")); strings.HasSuffix(end, " AND") || strings.HasSuffix(end, " OR") {
+		conj = ""
+		if len(end) == len(c.sql) {
+			conj = " "
+		}
+	}
+	if c.sql == "" {
+		c.keyword = " WHERE "
+	} else if len(conditions) > 1 {
+		str = conj + "(" + str + ")"
+	} else {
+		str = conj + str
+	}
+	c.sql += str
+	c.args = append(c.args, args...)
+	return b
 }
 
 // And adds one or more conditions to the query using AND logic.
 // If a single condition is provided, it adds "AND condition".
 // If multiple conditions are provided, it adds "AND (condition1 AND condition2 ...)"
+// If the WHERE clause is empty, it begins it with the conditions instead.
 // It returns the Builder instance for method chaining.
 func (b *Builder) And(conditions ...*Condition) *Builder {
-	switch len(conditions) {
-	case 0:
+	if len(conditions) <= 0 {
 		return b
-	case 1:
-		b.query.WriteString(" AND ")
-		b.addConditions(conditions...)
-	default:
-		b.query.WriteString(" AND (")
-		b.addConditions(conditions...)
-		b.query.WriteString(")")
 	}
+	b.where(" AND ", conditions...)
 	// if len(b.where) > 0 {
 	// 	b.where[0] = "(" + b.where[0]
 	// 	b.where[len(b.where)-1] = b.where[len(b.where)-1] + ") AND"
@@ -479,19 +1302,13 @@ func (b *Builder) And(conditions ...*Condition) *Builder {
 // Or adds one or more conditions to the query using OR logic.
 // If a single condition is provided, it adds "OR condition".
 // If multiple conditions are provided, it adds "OR (condition1 OR condition2 ...)"
+// If the WHERE clause is empty, it begins it with the conditions instead.
 // It returns the Builder instance for method chaining.
 func (b *Builder) Or(conditions ...*Condition) *Builder {
-	switch len(conditions) {
-	case 0:
+	if len(conditions) <= 0 {
 		return b
-	case 1:
-		b.query.WriteString(" OR ")
-		b.addConditions(conditions...)
-	default:
-		b.query.WriteString(" OR (")
-		b.addConditions(conditions...)
-		b.query.WriteString(")")
 	}
+	b.where(" OR ", conditions...)
 
 	// if len(b.where) > 0 {
 	// 	b.where[0] = "(" + b.where[0]
@@ -504,6 +1321,7 @@ func (b *Builder) Or(conditions ...*Condition) *Builder {
 
 // In adds an IN condition to the query for the specified field and values.
 // It is equivalent to "field IN (value1, value2, ...)"
+// It is combined with the existing conditions of the WHERE clause using AND.
 // It returns the Builder instance for method chaining.
 //
 // Example:
@@ -524,12 +1342,13 @@ func (b *Builder) Or(conditions ...*Condition) *Builder {
 //	  .Where(builder.In("status", "active", "pending"))
 //	// Generates: SELECT * FROM users WHERE `status` IN (?, ?)
 func (b *Builder) In(field string, values ...interface{}) *Builder {
-	b.addConditions(In(field, values...))
+	b.where(" AND ", In(field, values...))
 	return b
 }
 
 // NotIn adds a NOT IN condition to the query for the specified field and values.
 // It is equivalent to "field NOT IN (value1, value2, ...)"
+// It is combined with the existing conditions of the WHERE clause using AND.
 // It returns the Builder instance for method chaining.
 //
 // Example:
@@ -537,12 +1356,13 @@ func (b *Builder) In(field string, values ...interface{}) *Builder {
 //	b.Select("*").From("users").NotIn("status", "deleted", "banned")
 //	// Generates: SELECT * FROM users WHERE status NOT IN (?, ?)
 func (b *Builder) NotIn(field string, values ...interface{}) *Builder {
-	b.addConditions(NotIn(field, values...))
+	b.where(" AND ", NotIn(field, values...))
 	return b
 }
 
 // Between adds a BETWEEN condition to the query for the specified field and range values.
 // It expects exactly two values defining the range (start and end).
+// It is combined with the existing conditions of the WHERE clause using AND.
 // It returns the Builder instance for method chaining.
 //
 // Example:
@@ -550,12 +1370,13 @@ func (b *Builder) NotIn(field string, values ...interface{}) *Builder {
 //	b.Select("*").From("orders").Between("amount", 100, 1000)
 //	// Generates: SELECT * FROM orders WHERE amount BETWEEN ? AND ?
 func (b *Builder) Between(field string, values ...interface{}) *Builder {
-	b.addConditions(Between(field, values...))
+	b.where(" AND ", Between(field, values...))
 	return b
 }
 
 // NotBetween adds a NOT BETWEEN condition to the query for the specified field and range values.
 // It expects exactly two values defining the range (start and end).
+// It is combined with the existing conditions of the WHERE clause using AND.
 // It returns the Builder instance for method chaining.
 //
 // Example:
@@ -563,25 +1384,27 @@ func (b *Builder) Between(field string, values ...interface{}) *Builder {
 //	b.Select("*").From("orders").NotBetween("amount", 0, 100)
 //	// Generates: SELECT * FROM orders WHERE amount NOT BETWEEN ? AND ?
 func (b *Builder) NotBetween(field string, values ...interface{}) *Builder {
-	b.addConditions(NotBetween(field, values...))
+	b.where(" AND ", NotBetween(field, values...))
 	return b
 }
 
-// Where begins the WHERE clause of a query with the specified conditions.
+// Where begins the WHERE clause of a query with the specified conditions,
+// replacing any previous WHERE clause. Use And and Or to add more conditions.
 // If no conditions are provided, it adds "WHERE 1".
 // It returns the Builder instance for method chaining.
 func (b *Builder) Where(conditions ...*Condition) *Builder {
-	b.query.WriteString(" WHERE ")
+	c := b.replaceClause(WhereClause, " WHERE ")
 	if len(conditions) == 0 {
-		b.query.WriteString("1")
+		c.sql = "1"
 		return b
 	}
 
-	b.addConditions(conditions...)
+	c.sql, c.args = b.buildConditions(conditions...)
 	return b
 }
 
-// WhereRaw adds a raw WHERE clause without any escaping or parameter binding.
+// WhereRaw sets a raw WHERE clause without any escaping or parameter binding,
+// replacing any previous WHERE clause.
 // This method is useful when you need to write complex WHERE conditions that
 // cannot be easily expressed using the standard condition builders.
 // It returns the Builder instance for method chaining.
@@ -605,9 +1428,9 @@ func (b *Builder) Where(conditions ...*Condition) *Builder {
 //	  .WhereRaw("FIND_IN_SET(?, roles)", "admin")
 //	// Generates: SELECT * FROM users WHERE FIND_IN_SET(?, roles)
 func (b *Builder) WhereRaw(str string, args ...interface{}) *Builder {
-	b.query.WriteString(" WHERE ")
-	b.query.WriteString(str)
-	b.queryArgs = append(b.queryArgs, args...)
+	c := b.replaceClause(WhereClause, " WHERE ")
+	c.sql = str
+	c.args = append(c.args, args...)
 
 	return b
 }
@@ -633,7 +1456,8 @@ func (b *Builder) WhereRaw(str string, args ...interface{}) *Builder {
 //   - err: Error if validation fails
 //
 // Supported operators:
-//   - Single value: =, !=, <>, >, >=, <, <=, LIKE, NOT LIKE
+//   - No value: IS NULL, IS NOT NULL
+//   - Single value: =, !=, <>, >, >=, <, <=, LIKE, NOT LIKE, IS [NOT] DISTINCT FROM
 //   - Multiple values: IN, NOT IN
 //   - Range values: BETWEEN, NOT BETWEEN
 //
@@ -650,6 +1474,19 @@ func (b *Builder) buildCondition(cond *Condition) (str string, queryArgs []inter
 	str = ""
 	queryArgs = []interface{}{}
 
+	if cond.Conditions != nil {
+		return b.buildComposite(cond)
+	}
+
+	if b.nilAsNull && len(cond.Values) == 1 && isNil(cond.Values[0]) {
+		switch cond.Operator {
+		case "=":
+			cond = newCondition(cond.AndOr, cond.Field, "IS NULL", nil)
+		case "!=", "<>":
+			cond = newCondition(cond.AndOr, cond.Field, "IS NOT NULL", nil)
+		}
+	}
+
 	if opValue, ok := operMap[cond.Operator]; !ok {
 		// return "", queryArgs,
 		err = fmt.Errorf("invalid operator:(operator:%s[field:%s])", cond.Operator, cond.Field)
@@ -661,7 +1498,7 @@ func (b *Builder) buildCondition(cond *Condition) (str string, queryArgs []inter
 				break
 			}
 			fallthrough
-		case 1, 2:
+		case 0, 1, 2:
 			err = fmt.Errorf("invalid number of values with operator:(%s[field:%s])", cond.Operator, cond.Field)
 			return
 		}
@@ -679,6 +1516,15 @@ func (b *Builder) buildCondition(cond *Condition) (str string, queryArgs []inter
 	// "NOT LIKE":    1,
 	// "BETWEEN":     2,
 	// "NOT BETWEEN": 2,
+	values := make([]string, len(cond.Values))
+	for i, v := range cond.Values {
+		var args []interface{}
+		if values[i], args, err = b.buildValue(v); err != nil {
+			return
+		}
+		queryArgs = append(queryArgs, args...)
+	}
+
 	placeholders := ""
 	switch strings.ToLower((cond.Operator)) {
 	case "=",
@@ -686,24 +1532,319 @@ func (b *Builder) buildCondition(cond *Condition) (str string, queryArgs []inter
 		">", ">=",
 		"<", "<=",
 		"like", "not like":
-		placeholders = "?"
+		placeholders = values[0]
+	case "is null", "is not null":
+		str = b.escapeField(cond.Field) + " " + cond.Operator
+		return
+	case "is distinct from", "is not distinct from":
+		str = b.dialector.DistinctFrom(b.escapeField(cond.Field), values[0], strings.EqualFold(cond.Operator, "IS DISTINCT FROM"))
+		return
 	case "in", "not in":
-		placeholders = "(?" + strings.Repeat(", ?", len(cond.Values)-1) + ")"
+		if len(values) == 1 && isSubquery(cond.Values[0]) {
+			placeholders = values[0]
+			break
+		}
+		placeholders = "(" + strings.Join(values, ", ") + ")"
 	case "between", "not between":
-		placeholders += "? AND ?"
+		placeholders += values[0] + " AND " + values[1]
+	case "exists", "not exists":
+		if !isSubquery(cond.Values[0]) {
+			err = fmt.Errorf("invalid sub-query with operator:(%s)", cond.Operator)
+			return
+		}
+		str = cond.Operator + " " + values[0]
+		return
 		// default:
 	}
 
-	queryArgs = append(queryArgs, cond.Values...)
+	str += b.escapeField(cond.Field) + " " + cond.Operator + " " + placeholders
 
-	str += b.Escape(cond.Field) + " " + cond.Operator + " " + placeholders
+	return
+}
 
+// buildComposite builds a composite condition created by AllOf, AnyOf or Not,
+// rendering its nested conditions in parentheses.
+func (b *Builder) buildComposite(cond *Condition) (str string, queryArgs []interface{}, err error) {
+	queryArgs = []interface{}{}
+	parts := make([]string, 0, len(cond.Conditions))
+	grouped := false // whether the last nested condition is an AND/OR group
+	for _, nested := range cond.Conditions {
+		if nested == nil {
+			continue
+		}
+		part, args, err := b.buildCondition(nested)
+		if err != nil {
+			return "", nil, err
+		}
+		parts = append(parts, part)
+		queryArgs = append(queryArgs, args...)
+		grouped = nested.Conditions != nil && !strings.EqualFold(nested.Operator, "NOT")
+	}
+	if len(parts) <= 0 {
+		return "", nil, fmt.Errorf("%w: no conditions with operator:(%s)", ErrEmptyCondition, cond.Operator)
+	}
+
+	switch strings.ToUpper(cond.Operator) {
+	case "AND", "OR":
+		str = "(" + strings.Join(parts, " "+strings.ToUpper(cond.Operator)+" ") + ")"
+	case "NOT":
+		if len(parts) > 1 {
+			return "", nil, fmt.Errorf("invalid number of conditions with operator:(%s)", cond.Operator)
+		}
+		if grouped {
+			// AND/OR groups are already in parentheses.
+			str = "NOT " + parts[0]
+		} else {
+			str = "NOT (" + parts[0] + ")"
+		}
+	default:
+		err = fmt.Errorf("invalid composite operator:(operator:%s)", cond.Operator)
+	}
 	return
 }
 
-// OrderBy specifies the ORDER BY clause with the given conditions.
+// buildValue returns the SQL fragment and arguments for a single condition value.
+// A Column value is rendered as an escaped identifier, an Expr is rendered as is
+// (with its window if any), Default is rendered as the DEFAULT keyword,
+// a sub-query is rendered in parentheses with their own arguments,
+// anything else is bound through a placeholder.
+func (b *Builder) buildValue(v interface{}) (string, []interface{}, error) {
+	switch v := v.(type) {
+	case Column:
+		return b.Escape(string(v)), nil, nil
+	case Excluded:
+		return b.dialector.Excluded(b.Escape(string(v))), nil, nil
+	case defaultValue:
+		keyword, err := b.dialector.DefaultValue()
+		return keyword, nil, err
+	case Expr:
+		query, args := b.buildExpr(v)
+		return query, args, nil
+	case *Expr:
+		if v != nil {
+			query, args := b.buildExpr(*v)
+			return query, args, nil
+		}
+	case *Query, *Builder:
+		query, args, err := b.buildSubquery(v)
+		if err != nil {
+			return "", nil, err
+		}
+		return "(" + query + ")", args, nil
+	}
+	return "?", []interface{}{v}, nil
+}
+
+// buildExpr renders an expression along with its arguments,
+// followed by its OVER part when it is a window function call.
+func (b *Builder) buildExpr(e Expr) (string, []interface{}) {
+	if e.Window == nil {
+		return e.SQL, e.Args
+	}
+	over, args := b.buildOver(e.Window)
+	return e.SQL + " " + over, append(append([]interface{}{}, e.Args...), args...)
+}
+
+// isNil reports whether v is nil or a nil pointer, map, slice or interface,
+// which are all bound as NULL.
+func isNil(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// isSubquery reports whether v is a value that buildValue renders as a sub-query.
+func isSubquery(v interface{}) bool {
+	switch v.(type) {
+	case *Query, *Builder:
+		return true
+	}
+	return false
+}
+
+// buildSubquery returns the query string and arguments of a sub-query given as
+// a built *Query or an un-built *Builder. The query string always uses "?"
+// placeholders, so that they can be renumbered along with the outer query.
+// A *Builder is read without being built, so it can be used again afterwards; since its
+// identifiers are already escaped, it must use the same dialect as b.
+func (b *Builder) buildSubquery(sub interface{}) (query string, args []interface{}, err error) {
+	switch sub := sub.(type) {
+	case *Query:
+		if sub == nil {
+			return "", nil, ErrEmptySubquery
+		}
+		query, args = unbind(sub.Query, sub.Args)
+	case *Builder:
+		if sub == nil {
+			return "", nil, ErrEmptySubquery
+		}
+		if len(sub.ErrList) > 0 {
+			return "", nil, fmt.Errorf("invalid sub-query: %v", sub.ErrList[0])
+		}
+		if sub.dialector != b.dialector {
+			return "", nil, fmt.Errorf("invalid sub-query: %w", ErrDialectorMismatch)
+		}
+		query, args = sub.render()
+	default:
+		return "", nil, ErrEmptySubquery
+	}
+	if query == "" {
+		return "", nil, ErrEmptySubquery
+	}
+	return query, args, nil
+}
+
+// aggregateCall matches the aggregate calls kept as is in the fields of HAVING conditions:
+// a function name and a single argument which is "*" or a possibly qualified column,
+// optionally preceded by DISTINCT in any case, e.g. "COUNT(*)" or "SUM(DISTINCT o.amount)".
+var aggregateCall = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*\((?i:DISTINCT )?(?:\*|[A-Za-z0-9_$]+(?:\.[A-Za-z0-9_$]+)*)\)$`)
+
+// escapeField escapes the field of a condition as a possibly qualified identifier.
+// Aggregate calls matching aggregateCall are kept as is in HAVING conditions, their
+// function name and column being validated against the identifier pattern if any.
+func (b *Builder) escapeField(field string) string {
+	if !b.having || !aggregateCall.MatchString(field) {
+		return b.Escape(field)
+	}
+	if b.identifierPattern != nil {
+		i := strings.IndexByte(field, '(')
+		b.validateIdentifiers(field[:i])
+		arg := field[i+1 : len(field)-1]
+		if len(arg) > 9 && strings.EqualFold(arg[:9], "DISTINCT ") {
+			arg = arg[9:]
+		}
+		if _, err := strconv.Atoi(arg); err != nil {
+			b.validateIdentifiers(arg)
+		}
+	}
+	return field
+}
+
+// escapeColumn escapes a column of a SELECT list, which may be qualified
+// and may carry an alias written as "column AS alias".
+func (b *Builder) escapeColumn(column string) string {
+	if i := aliasIndex(column); i > 0 {
+		alias := strings.TrimSpace(column[i+4:])
+		return b.Escape(strings.TrimSpace(column[:i])) + " AS " + b.Escape(alias)
+	}
+	return b.Escape(column)
+}
+
+// aliasIndex returns the index of the last " AS " of column outside parentheses,
+// as in "CAST(x AS INT) AS y", or -1 if there is none.
+func aliasIndex(column string) int {
+	depth := 0
+	for i := len(column) - 1; i >= 0; i-- {
+		switch column[i] {
+		case ')':
+			depth++
+		case '(':
+			depth--
+		case ' ':
+			if depth == 0 && i >= 3 && strings.EqualFold(column[i-3:i+1], " AS ") {
+				return i - 3
+			}
+		}
+	}
+	return -1
+}
+
+// escapeTable escapes a table name with an optional alias,
+// written as "users AS u" or "users u".
+func (b *Builder) escapeTable(table string) string {
+	parts := strings.Fields(table)
+	switch {
+	case len(parts) == 2:
+		return b.Escape(parts[0]) + " AS " + b.Escape(parts[1])
+	case len(parts) == 3 && strings.EqualFold(parts[1], "AS"):
+		return b.Escape(parts[0]) + " AS " + b.Escape(parts[2])
+	}
+	return b.Escape(table)
+}
+
+// GroupBy sets the GROUP BY clause with the specified fields, replacing any previous one.
+// Fields may be qualified (e.g. "users.id"), use GroupByExpr for expressions.
+// It returns the Builder instance for method chaining.
+//
+// Example:
+//
+//	b.Select("user_id").From("orders").GroupBy("user_id")
+//	// Generates: SELECT `user_id` FROM `orders` GROUP BY `user_id`
+func (b *Builder) GroupBy(fields ...string) *Builder {
+	exprs := make([]interface{}, len(fields))
+	for i, field := range fields {
+		exprs[i] = field
+	}
+	return b.GroupByExpr(exprs...)
+}
+
+// GroupByExpr sets the GROUP BY clause like GroupBy, where fields can be field names
+// or Expr values rendered as is with their arguments.
+// It returns the Builder instance for method chaining.
+//
+// Example:
+//
+//	b.Select("user_id").From("orders").GroupByExpr("user_id", builder.NewExpr("DATE(created_at)"))
+//	// Generates: SELECT `user_id` FROM `orders` GROUP BY `user_id`, DATE(created_at)
+func (b *Builder) GroupByExpr(fields ...interface{}) *Builder {
+	if len(fields) <= 0 {
+		return b
+	}
+	var (
+		parts = make([]string, len(fields))
+		args  []interface{}
+	)
+	for i, field := range fields {
+		if field, ok := field.(string); ok {
+			parts[i] = b.Escape(field)
+			continue
+		}
+		part, fieldArgs, err := b.buildValue(field)
+		if err != nil {
+			b.ErrList = append(b.ErrList, err)
+			part = fmt.Sprintf("{error: %s}", err)
+		}
+		parts[i] = part
+		args = append(args, fieldArgs...)
+	}
+	c := b.replaceClause(GroupByClause, " GROUP BY ")
+	c.sql = strings.Join(parts, ", ")
+	c.args = args
+	return b
+}
+
+// Having sets the HAVING clause with the specified conditions, combined the same way as in Where
+// and replacing any previous HAVING clause.
+// Condition fields may be aggregate calls with a single column or "*" argument, such as
+// "COUNT(*)" or "SUM(o.amount)", which are rendered without escaping. Any other field
+// is escaped as an identifier.
+// It returns the Builder instance for method chaining.
+//
+// Example:
+//
+//	b.Select("user_id").From("orders").GroupBy("user_id").Having(builder.Gt("SUM(amount)", 100))
+//	// Generates: SELECT `user_id` FROM `orders` GROUP BY `user_id` HAVING SUM(amount) > ?
+func (b *Builder) Having(conditions ...*Condition) *Builder {
+	if len(conditions) <= 0 {
+		return b
+	}
+	c := b.replaceClause(HavingClause, " HAVING ")
+	b.having = true
+	c.sql, c.args = b.buildConditions(conditions...)
+	b.having = false
+	return b
+}
+
+// OrderBy specifies the ORDER BY clause with the given conditions, replacing any previous one.
 // Each condition determines the field and sort direction (ASC/DESC).
 // Multiple conditions can be combined to sort by multiple fields.
+// Fields may be qualified, use AscExpr and DescExpr for expressions.
+// Without conditions, the ORDER BY clause is removed.
 // It returns the Builder instance for method chaining.
 //
 // Parameters:
@@ -718,16 +1859,43 @@ func (b *Builder) buildCondition(cond *Condition) (str string, queryArgs []inter
 //	  )
 //	// Generates: SELECT * FROM users ORDER BY `created_at` ASC, `last_login` DESC
 func (b *Builder) OrderBy(conditions ...*Condition) *Builder {
-	b.query.WriteString(" ORDER BY ")
+	order, args := b.buildOrderBy(conditions)
+	if order == "" {
+		return b.RemoveClause(OrderByClause)
+	}
+	c := b.replaceClause(OrderByClause, " ORDER BY ")
+	c.sql = order
+	c.args = args
 
-	condStrSlice := []string{}
+	return b
+}
+
+// buildOrderBy renders a list of ordering conditions, as used by ORDER BY clauses
+// and windows, along with the arguments of their expressions.
+// Errors are collected in the Builder's ErrList.
+func (b *Builder) buildOrderBy(conditions []*Condition) (string, []interface{}) {
+	var (
+		condStrSlice = []string{}
+		args         []interface{}
+	)
 
 	for _, cond := range conditions {
 		if cond == nil {
 			continue
 		}
 		var condStr strings.Builder
-		condStr.WriteString(b.Escape(cond.Field))
+		if cond.Field == "" && len(cond.Values) == 1 {
+			// Created by AscExpr or DescExpr.
+			expr, exprArgs, err := b.buildValue(cond.Values[0])
+			if err != nil {
+				b.ErrList = append(b.ErrList, err)
+				expr = fmt.Sprintf("{error: %s}", err)
+			}
+			condStr.WriteString(expr)
+			args = append(args, exprArgs...)
+		} else {
+			condStr.WriteString(b.Escape(cond.Field))
+		}
 		if cond.Asc {
 			condStr.WriteString(" ASC")
 		} else {
@@ -735,16 +1903,19 @@ func (b *Builder) OrderBy(conditions ...*Condition) *Builder {
 		}
 		condStrSlice = append(condStrSlice, condStr.String())
 	}
-	b.query.WriteString(strings.Join(condStrSlice, ", "))
-
-	return b
+	return strings.Join(condStrSlice, ", "), args
 }
 
-// Limit adds a LIMIT clause to the query to restrict the number of rows returned.
+// Limit sets the LIMIT clause of the query to restrict the number of rows returned.
 // It can be used in two ways:
-// 1. With a single argument to limit the number of rows
+// 1. With a single argument to limit the number of rows, keeping any offset set by Offset
 // 2. With two arguments to specify both offset and limit
 //
+// The clause is rendered by the current SQL dialect, e.g. "LIMIT 20, 10" for MySQL
+// and "LIMIT 10 OFFSET 20" for PostgreSQL and SQLite.
+// Use Offset or Page for a less ambiguous argument order.
+// Negative values are recorded in ErrList as ErrNegativeLimit and leave the clause unchanged.
+//
 // Parameters:
 //   - limitOffset: One or two integers:
 //   - With one argument: The maximum number of rows to return
@@ -757,18 +1928,234 @@ func (b *Builder) OrderBy(conditions ...*Condition) *Builder {
 //	// Skip 20 rows and return next 10
 //	b.Select("*").From("users").Limit(20, 10)
 func (b *Builder) Limit(limitOffset ...int) *Builder {
-	if len(limitOffset) == 1 {
-		b.query.WriteString(" LIMIT ")
-		b.query.WriteString(strconv.Itoa(limitOffset[0]))
-	} else {
-		b.query.WriteString(" LIMIT ")
-		b.query.WriteString(strconv.Itoa(limitOffset[0]))
-		b.query.WriteString(", ")
-		b.query.WriteString(strconv.Itoa(limitOffset[1]))
+	if !b.checkPagination(limitOffset...) {
+		return b
+	}
+	switch len(limitOffset) {
+	case 0:
+		return b
+	case 1:
+		offset := -1
+		if b.pagination != nil {
+			offset = b.pagination.offset
+		}
+		return b.paginate(limitOffset[0], offset)
+	default:
+		return b.paginate(limitOffset[1], limitOffset[0])
+	}
+}
+
+// Offset sets the OFFSET of the query to skip the given number of rows.
+// The offset is merged with the limit set by Limit, before or after it,
+// otherwise an offset-only clause is rendered by the current SQL dialect.
+// A negative offset is recorded in ErrList as ErrNegativeLimit.
+// It returns the Builder instance for method chaining.
+//
+// Example:
+//
+//	b.Select("*").From("users").Limit(10).Offset(20)
+//	// Generates: SELECT * FROM `users` LIMIT 20, 10 (MySQL)
+//	// Generates: SELECT * FROM "users" LIMIT 10 OFFSET 20 (PostgreSQL)
+func (b *Builder) Offset(offset int) *Builder {
+	if !b.checkPagination(offset) {
+		return b
+	}
+	limit := -1
+	if b.pagination != nil {
+		limit = b.pagination.limit
+	}
+	return b.paginate(limit, offset)
+}
+
+// Page sets the LIMIT clause for the given 1-based page number with perPage rows per page.
+// Page numbers lower than 1 are treated as the first page, and a negative perPage
+// is recorded in ErrList as ErrNegativeLimit.
+// It returns the Builder instance for method chaining.
+//
+// Example:
+//
+//	b.Select("*").From("users").Page(3, 10)
+//	// Generates: SELECT * FROM "users" LIMIT 10 OFFSET 20 (PostgreSQL)
+func (b *Builder) Page(page, perPage int) *Builder {
+	if !b.checkPagination(perPage) {
+		return b
+	}
+	if page < 1 {
+		page = 1
+	}
+	return b.paginate(perPage, (page-1)*perPage)
+}
+
+// checkPagination reports whether the given limit and offset values are valid,
+// recording ErrNegativeLimit in ErrList for the first negative one.
+func (b *Builder) checkPagination(values ...int) bool {
+	for _, v := range values {
+		if v < 0 {
+			b.ErrList = append(b.ErrList, fmt.Errorf("%w: %d", ErrNegativeLimit, v))
+			return false
+		}
+	}
+	return true
+}
+
+// SetNilAsNull sets whether equality conditions with a nil value, such as Eq("deleted_at", nil),
+// render "IS NULL" instead of "= ?", which never matches, and inequality conditions
+// ("!=" or "<>", e.g. NotEq) render "IS NOT NULL".
+// It returns the Builder instance for method chaining.
+//
+// Example:
+//
+//	b.SetNilAsNull(true).Select("*").From("users").Where(builder.Eq("deleted_at", nil))
+//	// Generates: SELECT * FROM `users` WHERE `deleted_at` IS NULL
+func (b *Builder) SetNilAsNull(nilAsNull bool) *Builder {
+	b.nilAsNull = nilAsNull
+	return b
+}
+
+// SetBindLimit sets whether LIMIT and OFFSET values are bound as query arguments
+// instead of being written into the query, which allows the same statement
+// to be prepared once and reused for every page.
+// It returns the Builder instance for method chaining.
+func (b *Builder) SetBindLimit(bind bool) *Builder {
+	b.bindLimit = bind
+	return b
+}
+
+// pagination records the limit and offset of the LIMIT clause, so that Limit and Offset can complete each other.
+type pagination struct {
+	limit, offset int // -1 when absent
+}
+
+// Markers used to find the order of the bound limit and offset in a rendered clause.
+const (
+	limitMarker  = "\x00limit\x00"
+	offsetMarker = "\x00offset\x00"
+)
+
+// paginate sets the pagination clause rendered by the current SQL dialect.
+// A negative limit or offset means the value is absent.
+func (b *Builder) paginate(limit, offset int) *Builder {
+	var limitStr, offsetStr string
+	if limit >= 0 {
+		limitStr = strconv.Itoa(limit)
+	}
+	if offset >= 0 {
+		offsetStr = strconv.Itoa(offset)
+	}
+
+	b.pagination = &pagination{limit: limit, offset: offset}
+	c := b.replaceClause(LimitClause, " ")
+	if !b.bindLimit {
+		c.sql = b.dialector.LimitOffset(limitStr, offsetStr)
+		return b
+	}
+
+	if limitStr != "" {
+		limitStr = limitMarker
+	}
+	if offsetStr != "" {
+		offsetStr = offsetMarker
+	}
+	clause := b.dialector.LimitOffset(limitStr, offsetStr)
+	limitIdx, offsetIdx := strings.Index(clause, limitMarker), strings.Index(clause, offsetMarker)
+	switch {
+	case limitIdx >= 0 && offsetIdx >= 0 && offsetIdx < limitIdx:
+		c.args = append(c.args, offset, limit)
+	case limitIdx >= 0 && offsetIdx >= 0:
+		c.args = append(c.args, limit, offset)
+	case limitIdx >= 0:
+		c.args = append(c.args, limit)
+	case offsetIdx >= 0:
+		c.args = append(c.args, offset)
+	}
+	clause = strings.Replace(clause, limitMarker, "?", 1)
+	c.sql = strings.Replace(clause, offsetMarker, "?", 1)
+
+	return b
+}
+
+// ForUpdate adds a FOR UPDATE clause to a SELECT query, locking the selected rows
+// against concurrent updates until the end of the transaction. If tables are given,
+// only the rows of these tables (or aliases) are locked. Use NoWait or SkipLocked
+// afterwards to change how rows locked by other transactions are handled.
+// An error is recorded in ErrList when the current SQL dialect does not support
+// row locking (e.g. SQLite) or the query is not a SELECT.
+// It returns the Builder instance for method chaining.
+//
+// Example:
+//
+//	b.Select("*").From("jobs").Where(builder.Eq("status", "pending")).OrderBy(builder.Asc("id")).Limit(10).ForUpdate().SkipLocked()
+//	// Generates: SELECT * FROM `jobs` WHERE `status` = ? ORDER BY `id` ASC LIMIT 10 FOR UPDATE SKIP LOCKED
+func (b *Builder) ForUpdate(tables ...string) *Builder {
+	return b.lock(&locking{strength: "UPDATE", tables: b.escapeAll(tables)})
+}
+
+// ForShare adds a FOR SHARE clause to a SELECT query like ForUpdate, locking the
+// selected rows against concurrent updates while letting other transactions read them.
+// It returns the Builder instance for method chaining.
+func (b *Builder) ForShare(tables ...string) *Builder {
+	return b.lock(&locking{strength: "SHARE", tables: b.escapeAll(tables)})
+}
+
+// NoWait makes the locking clause set by ForUpdate or ForShare fail at once, instead of
+// waiting, when a selected row is locked by another transaction.
+// It returns the Builder instance for method chaining.
+func (b *Builder) NoWait() *Builder {
+	return b.lockWait("NOWAIT")
+}
+
+// SkipLocked makes the locking clause set by ForUpdate or ForShare skip the rows
+// locked by other transactions, e.g. to let several workers consume a job queue.
+// It returns the Builder instance for method chaining.
+func (b *Builder) SkipLocked() *Builder {
+	return b.lockWait("SKIP LOCKED")
+}
+
+// locking records the locking clause of a SELECT query, so that NoWait and SkipLocked can complete it.
+type locking struct {
+	strength string   // "UPDATE" or "SHARE"
+	tables   []string // escaped tables of the OF list
+	wait     string   // "", "NOWAIT" or "SKIP LOCKED"
+}
+
+// lockWait sets the wait policy of the locking clause.
+func (b *Builder) lockWait(wait string) *Builder {
+	if b.locking == nil {
+		b.ErrList = append(b.ErrList, fmt.Errorf("%s requires a locking clause, use ForUpdate or ForShare first", wait))
+		return b
 	}
+	l := *b.locking
+	l.wait = wait
+	return b.lock(&l)
+}
+
+// lock sets the locking clause rendered by the current SQL dialect.
+// Errors are collected in the Builder's ErrList.
+func (b *Builder) lock(l *locking) *Builder {
+	switch b.sqlType {
+	case InsertSQL, ReplaceSQL, UpdateSQL, DeleteSQL:
+		b.ErrList = append(b.ErrList, fmt.Errorf("FOR %s is only valid for SELECT queries (sql type: %d)", l.strength, b.sqlType))
+		return b
+	}
+	clause, err := b.dialector.Lock(l.strength, l.tables, l.wait)
+	if err != nil {
+		b.ErrList = append(b.ErrList, err)
+		return b
+	}
+	b.locking = l
+	b.replaceClause(LockClause, " ").sql = clause
 	return b
 }
 
+// escapeAll escapes each of the given identifiers.
+func (b *Builder) escapeAll(identifiers []string) []string {
+	escaped := make([]string, len(identifiers))
+	for i, identifier := range identifiers {
+		escaped[i] = b.Escape(identifier)
+	}
+	return escaped
+}
+
 // Count begins a SELECT COUNT query.
 // It can count all rows (COUNT(1)) or specific fields/expressions.
 // It returns the Builder instance for method chaining.
@@ -787,14 +2174,13 @@ func (b *Builder) Limit(limitOffset ...int) *Builder {
 //	b.Count("DISTINCT status").From("orders")
 //	// Generates: SELECT COUNT(DISTINCT status) FROM orders
 func (b *Builder) Count(query ...string) *Builder {
-	b.renew(SelectSQL)
-	b.query.WriteString("SELECT COUNT(")
+	b.start(SelectSQL)
+	c := b.replaceClause(headClause, "SELECT COUNT(")
 	if len(query) <= 0 {
-		b.query.WriteString("1")
+		c.sql = "1)"
 	} else {
-		b.query.WriteString(strings.TrimSpace(strings.Join(query, " ")))
+		c.sql = strings.TrimSpace(strings.Join(query, " ")) + ")"
 	}
-	b.query.WriteString(")")
 
 	return b
 }
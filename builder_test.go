@@ -1,8 +1,13 @@
 package builder
 
 import (
+	"errors"
+	"fmt"
 	"reflect"
+	"strconv"
+	"sync"
 	"testing"
+	"time"
 )
 
 type Dao struct {
@@ -121,7 +126,7 @@ func TestSelect(t *testing.T) {
 	if !reflect.DeepEqual(wantArgs, args) {
 		t.Errorf("\ngotArgs:\n%#v\nwantArgs:\n%#v\n", args, wantArgs)
 	}
-	want = "SELECT `id`, `name`, `age`, `sex`, `birthday` FROM `user` WHERE {error: invalid operator:(operator:![field:test])} AND `name` IN (?, ?) OR `sex` = ? OR `name` = ? AND {error: invalid number of values with operator:(=[field:test_field])} ORDER BY `age` DESC, `name` ASC LIMIT 100 LIMIT 0, 100"
+	want = "SELECT `id`, `name`, `age`, `sex`, `birthday` FROM `user` WHERE {error: invalid operator:(operator:![field:test])} AND `name` IN (?, ?) OR `sex` = ? OR `name` = ? AND {error: invalid number of values with operator:(=[field:test_field])} ORDER BY `age` DESC, `name` ASC LIMIT 0, 100"
 	wantArgs = []interface{}{"coder", "hacker", "female", "coder"}
 	b.Select(d.Fields()...).
 		From("user").
@@ -186,6 +191,19 @@ func TestSelect(t *testing.T) {
 		t.Errorf("\ngotArgs:\n%#v\nwantArgs:\n%#v\n", args, wantArgs)
 	}
 
+	want = "SELECT * FROM `user` WHERE `a` = ? AND `b` IN (?, ?) AND `c` NOT IN (?) AND `d` BETWEEN ? AND ? AND `e` NOT BETWEEN ? AND ?"
+	wantArgs = []interface{}{1, 2, 3, 4, 5, 6, 7, 8}
+	q, err = b.Select("*").From("user").Where(Eq("a", 1)).In("b", 2, 3).NotIn("c", 4).Between("d", 5, 6).NotBetween("e", 7, 8).Build()
+	if err != nil {
+		t.Errorf("error: %s", err)
+	}
+	if want != q.Query {
+		t.Errorf("\ngot:\n%s\nwant:\n%s\n", q.Query, want)
+	}
+	if !reflect.DeepEqual(wantArgs, q.Args) {
+		t.Errorf("\ngotArgs:\n%#v\nwantArgs:\n%#v\n", q.Args, wantArgs)
+	}
+
 	want = "SELECT * FROM `user` WHERE {error: invalid number of values with operator:(IN[field:city_id])}"
 	inCityIDs = &Condition{
 		Field:    "city_id",
@@ -531,7 +549,7 @@ func TestSetDialector(t *testing.T) {
 	b.SetDialector(mysqlDialector)
 	t.Logf("mysql escape char:[%v]", b.EscapeChar())
 
-	want = `SELECT * FROM "user" WHERE 1 AND ("name" = ? OR "sex" = ?)`
+	want = `SELECT * FROM "user" WHERE 1 AND ("name" = $1 OR "sex" = $2)`
 	wantArgs = []interface{}{"coder", "female"}
 	b.SetDialector(postgresDialector)
 
@@ -622,3 +640,1941 @@ func TestCount(t *testing.T) {
 		t.Errorf("\ngot:\n%s\nlast query:\n%s\n", got, lastQuery.Query)
 	}
 }
+
+func TestPostgresPlaceholders(t *testing.T) {
+	var (
+		got, want      string
+		args, wantArgs []interface{}
+		err            error
+		q              *Query
+	)
+	b.SetDialector(postgresDialector)
+	defer b.SetDialector(mysqlDialector)
+
+	want = `/* ? */ SELECT * FROM "user" WHERE "age" >= $1 AND "name" IN ($2, $3) AND "sex" = $4 OR "age" BETWEEN $5 AND $6 AND tags ? 'a?' AND "id" = $7`
+	wantArgs = []interface{}{1, "coder", "hacker", "female", 12, 36, 2}
+	b.Select("*").From("user").
+		Where(ageGT1, nameInNames).
+		And(sexEqFemale).
+		Or(ageBetweenCond).
+		Append(" AND tags ?? 'a?'").
+		Append(" AND \"id\" = ?", 2).
+		AppendPre("/* ? */ ")
+	q, err = b.Build()
+	got = q.Query
+	args = q.Args
+	if err != nil {
+		t.Errorf("error: %s", err)
+	}
+	if want != got {
+		t.Errorf("\ngot:\n%s\nwant:\n%s\n", got, want)
+	}
+	if !reflect.DeepEqual(wantArgs, args) {
+		t.Errorf("\ngotArgs:\n%#v\nwantArgs:\n%#v\n", args, wantArgs)
+	}
+
+	want = `UPDATE "user" SET "name" = $1, "age" = $2 WHERE "id" = $3`
+	wantArgs = []interface{}{"coder", 25, 1}
+	q, err = b.Update("user", NewFV("name", "coder"), NewFV("age", 25)).WhereRaw(`"id" = ?`, 1).Build()
+	got = q.Query
+	args = q.Args
+	if err != nil {
+		t.Errorf("error: %s", err)
+	}
+	if want != got {
+		t.Errorf("\ngot:\n%s\nwant:\n%s\n", got, want)
+	}
+	if !reflect.DeepEqual(wantArgs, args) {
+		t.Errorf("\ngotArgs:\n%#v\nwantArgs:\n%#v\n", args, wantArgs)
+	}
+
+	want = `INSERT INTO "user" ("id", "name") VALUES ($1, $2), ($3, $4)`
+	q, err = b.Insert("user", "id", "name").Values([]interface{}{1, "a"}, []interface{}{2, "b"}).Build()
+	got = q.Query
+	if err != nil {
+		t.Errorf("error: %s", err)
+	}
+	if want != got {
+		t.Errorf("\ngot:\n%s\nwant:\n%s\n", got, want)
+	}
+
+	want = `SELECT * FROM t WHERE a = $1 AND b = $2`
+	b.Raw("SELECT * FROM t WHERE a = ? AND b = ?", 1, 2)
+	if got = b.Query(); want != got {
+		t.Errorf("\ngot:\n%s\nwant:\n%s\n", got, want)
+	}
+
+	b.SetDialector(sqliteDialector)
+	want = `SELECT * FROM "user" WHERE "name" = ?`
+	q, _ = b.Select("*").From("user").Where(nameEqCoder).Build()
+	if got = q.Query; want != got {
+		t.Errorf("\ngot:\n%s\nwant:\n%s\n", got, want)
+	}
+
+	// the dialect cannot be changed once the statement is started
+	mixed := New().Select("*").From("t").Limit(10).SetDialector(postgresDialector).Where(Eq("a", 1))
+	if len(mixed.ErrList) != 1 || mixed.ErrList[0] != ErrDialectorChanged {
+		t.Errorf("ErrList = %v, want %v", mixed.ErrList, ErrDialectorChanged)
+	}
+	if _, err = mixed.Build(); err != ErrListIsNotEmpty {
+		t.Errorf("error = %v, want %v", err, ErrListIsNotEmpty)
+	}
+	if _, err = mixed.SetDialector(mysqlDialector).Select("*").From("t").SetDialector(mysqlDialector).Build(); err != nil {
+		t.Errorf("error: %s", err)
+	}
+}
+
+func TestJoin(t *testing.T) {
+	var (
+		got, want      string
+		args, wantArgs []interface{}
+		err            error
+		q              *Query
+	)
+
+	want = "SELECT * FROM `users` INNER JOIN `orders` AS `o` ON `users`.`id` = `o`.`user_id` AND `o`.`status` = ? LEFT JOIN `profiles` AS `p` ON `p`.`user_id` = `users`.`id` WHERE `users`.`age` > ?"
+	wantArgs = []interface{}{"paid", 18}
+	b.Select("*").From("users").
+		Join("orders o", On("users.id", "=", "o.user_id"), And("o.status", "=", "paid")).
+		LeftJoin("profiles AS p", On("p.user_id", "=", "users.id")).
+		Where(Gt("users.age", 18))
+	q, err = b.Build()
+	got = q.Query
+	args = q.Args
+	if err != nil {
+		t.Errorf("error: %s", err)
+	}
+	if want != got {
+		t.Errorf("\ngot:\n%s\nwant:\n%s\n", got, want)
+	}
+	if !reflect.DeepEqual(wantArgs, args) {
+		t.Errorf("\ngotArgs:\n%#v\nwantArgs:\n%#v\n", args, wantArgs)
+	}
+
+	want = "SELECT * FROM `a` RIGHT JOIN `b` USING (`id`, `kind`) FULL JOIN `c` ON `c`.`id` = `b`.`id` CROSS JOIN `d`"
+	q, err = b.Select("*").From("a").
+		RightJoin("b").Using("id", "kind").
+		FullJoin("c", Eq("c.id", Column("b.id"))).
+		CrossJoin("d").
+		Using().
+		Build()
+	got = q.Query
+	if err != nil {
+		t.Errorf("error: %s", err)
+	}
+	if want != got {
+		t.Errorf("\ngot:\n%s\nwant:\n%s\n", got, want)
+	}
+
+	b.SetDialector(postgresDialector)
+	defer b.SetDialector(mysqlDialector)
+	want = `SELECT * FROM "users" INNER JOIN "orders" AS "o" ON "users"."id" = "o"."user_id" AND "o"."total" > $1 WHERE "users"."id" = $2`
+	wantArgs = []interface{}{100, 1}
+	q, err = b.Select("*").From("users").
+		Join("orders o", On("users.id", "=", "o.user_id"), And("o.total", ">", 100)).
+		Where(Eq("users.id", 1)).
+		Build()
+	got = q.Query
+	args = q.Args
+	if err != nil {
+		t.Errorf("error: %s", err)
+	}
+	if want != got {
+		t.Errorf("\ngot:\n%s\nwant:\n%s\n", got, want)
+	}
+	if !reflect.DeepEqual(wantArgs, args) {
+		t.Errorf("\ngotArgs:\n%#v\nwantArgs:\n%#v\n", args, wantArgs)
+	}
+}
+
+func TestGroupByHaving(t *testing.T) {
+	var (
+		got, want      string
+		args, wantArgs []interface{}
+		err            error
+		q              *Query
+	)
+
+	want = "SELECT `user_id` FROM `orders` WHERE `status` = ? GROUP BY `user_id`, DATE(created_at) HAVING COUNT(*) > ? AND SUM(amount) >= ? ORDER BY `user_id` ASC"
+	wantArgs = []interface{}{"paid", 2, 100}
+	q, err = b.Select("user_id").From("orders").
+		Where(Eq("status", "paid")).
+		GroupByExpr("user_id", NewExpr("DATE(created_at)")).
+		Having(Gt("COUNT(*)", 2), And("SUM(amount)", ">=", 100)).
+		OrderBy(Asc("user_id")).
+		Build()
+	got = q.Query
+	args = q.Args
+	if err != nil {
+		t.Errorf("error: %s", err)
+	}
+	if want != got {
+		t.Errorf("\ngot:\n%s\nwant:\n%s\n", got, want)
+	}
+	if !reflect.DeepEqual(wantArgs, args) {
+		t.Errorf("\ngotArgs:\n%#v\nwantArgs:\n%#v\n", args, wantArgs)
+	}
+
+	want = "SELECT * FROM `orders`"
+	q, _ = b.Select("*").From("orders").GroupBy().Having().Build()
+	if got = q.Query; want != got {
+		t.Errorf("\ngot:\n%s\nwant:\n%s\n", got, want)
+	}
+
+	b.SetDialector(postgresDialector)
+	defer b.SetDialector(mysqlDialector)
+	want = `SELECT * FROM "orders" WHERE "status" = $1 GROUP BY "orders"."user_id" HAVING COUNT(*) > $2`
+	wantArgs = []interface{}{"paid", 2}
+	q, err = b.Select("*").From("orders").
+		Where(Eq("status", "paid")).
+		GroupBy("orders.user_id").
+		Having(Gt("COUNT(*)", 2)).
+		Build()
+	got = q.Query
+	args = q.Args
+	if err != nil {
+		t.Errorf("error: %s", err)
+	}
+	if want != got {
+		t.Errorf("\ngot:\n%s\nwant:\n%s\n", got, want)
+	}
+	if !reflect.DeepEqual(wantArgs, args) {
+		t.Errorf("\ngotArgs:\n%#v\nwantArgs:\n%#v\n", args, wantArgs)
+	}
+
+	// function-looking fields are only kept as is when they are aggregate calls in HAVING
+	want = `SELECT "COUNT(*)" FROM "orders" WHERE "COUNT(*)" = $1 GROUP BY "DATE(created_at)" ` +
+		`HAVING SUM(DISTINCT orders.amount) > $2 AND "id"")=1 OR (x)" = $3 AND "MAX(a, b)" = $4 ORDER BY "LOWER(name)" ASC`
+	q, err = b.Select("COUNT(*)").From("orders").
+		Where(Eq("COUNT(*)", 1)).
+		GroupBy("DATE(created_at)").
+		Having(Gt("SUM(DISTINCT orders.amount)", 1), And("id\")=1 OR (x)", "=", 2), And("MAX(a, b)", "=", 3)).
+		OrderBy(Asc("LOWER(name)")).
+		Build()
+	if err != nil {
+		t.Errorf("error: %s", err)
+	}
+	if got = q.Query; want != got {
+		t.Errorf("\ngot:\n%s\nwant:\n%s\n", got, want)
+	}
+}
+
+func TestSubquery(t *testing.T) {
+	var (
+		got, want      string
+		args, wantArgs []interface{}
+		err            error
+		q              *Query
+	)
+
+	sub := New().Select("user_id").From("orders").Where(Gt("amount", 100))
+	want = "SELECT * FROM `users` WHERE `status` = ? AND `id` IN (SELECT `user_id` FROM `orders` WHERE `amount` > ?) AND `level` = (SELECT MAX(level) FROM `levels`) OR EXISTS (SELECT `user_id` FROM `orders` WHERE `amount` > ?)"
+	wantArgs = []interface{}{"active", 100, 100}
+	q, err = b.Select("*").From("users").
+		Where(Eq("status", "active"), And("id", "IN", sub)).
+		And(Eq("level", New().Raw("SELECT MAX(level) FROM `levels`"))).
+		Or(Exists(sub)).
+		Build()
+	got = q.Query
+	args = q.Args
+	if err != nil {
+		t.Errorf("error: %s", err)
+	}
+	if want != got {
+		t.Errorf("\ngot:\n%s\nwant:\n%s\n", got, want)
+	}
+	if !reflect.DeepEqual(wantArgs, args) {
+		t.Errorf("\ngotArgs:\n%#v\nwantArgs:\n%#v\n", args, wantArgs)
+	}
+
+	want = "SELECT `user_id` FROM (SELECT `user_id` FROM `orders` WHERE `amount` > ?) AS `t` WHERE NOT EXISTS (SELECT * FROM `bans` WHERE `reason` = ?) AND `t`.`user_id` NOT IN (?, ?)"
+	wantArgs = []interface{}{100, "spam", 1, 2}
+	ban, _ := New().Select("*").From("bans").Where(Eq("reason", "spam")).Build()
+	q, err = b.Select("user_id").FromSub(sub, "t").
+		Where(NotExists(ban)).
+		And(NotIn("t.user_id", 1, 2)).
+		Build()
+	got = q.Query
+	args = q.Args
+	if err != nil {
+		t.Errorf("error: %s", err)
+	}
+	if want != got {
+		t.Errorf("\ngot:\n%s\nwant:\n%s\n", got, want)
+	}
+	if !reflect.DeepEqual(wantArgs, args) {
+		t.Errorf("\ngotArgs:\n%#v\nwantArgs:\n%#v\n", args, wantArgs)
+	}
+
+	// built postgres queries are renumbered when embedded
+	pg := New().SetDialector(postgresDialector)
+	inner, _ := pg.Select("id").From("users").Where(Eq("name", "coder"), AndSexEqFemale).Build()
+	if want = `SELECT "id" FROM "users" WHERE "name" = $1 AND "sex" = $2`; inner.Query != want {
+		t.Errorf("\ngot:\n%s\nwant:\n%s\n", inner.Query, want)
+	}
+	want = `DELETE FROM "posts" WHERE "status" = $1 AND "author_id" IN (SELECT "id" FROM "users" WHERE "name" = $2 AND "sex" = $3)`
+	wantArgs = []interface{}{"draft", "coder", "female"}
+	q, err = pg.Delete("posts").Where(Eq("status", "draft"), And("author_id", "IN", inner)).Build()
+	got = q.Query
+	args = q.Args
+	if err != nil {
+		t.Errorf("error: %s", err)
+	}
+	if want != got {
+		t.Errorf("\ngot:\n%s\nwant:\n%s\n", got, want)
+	}
+	if !reflect.DeepEqual(wantArgs, args) {
+		t.Errorf("\ngotArgs:\n%#v\nwantArgs:\n%#v\n", args, wantArgs)
+	}
+
+	// dollar signs in MySQL JSON paths and strings are not positional placeholders
+	doc := &Query{Query: "SELECT `id` FROM `docs` WHERE `a` = ? AND `b` = JSON_EXTRACT(doc, '$.k') AND `c` <> '$5'", Args: []interface{}{1}}
+	want = "SELECT * FROM `users` WHERE `id` IN (SELECT `id` FROM `docs` WHERE `a` = ? AND `b` = JSON_EXTRACT(doc, '$.k') AND `c` <> '$5')"
+	wantArgs = []interface{}{1}
+	q, err = b.Select("*").From("users").Where(In("id", doc)).Build()
+	if err != nil {
+		t.Errorf("error: %s", err)
+	}
+	if want != q.Query {
+		t.Errorf("\ngot:\n%s\nwant:\n%s\n", q.Query, want)
+	}
+	if !reflect.DeepEqual(wantArgs, q.Args) {
+		t.Errorf("\ngotArgs:\n%#v\nwantArgs:\n%#v\n", q.Args, wantArgs)
+	}
+
+	// un-built sub-queries must use the dialect of the outer query
+	mysqlSub := New().Select("id").From("orders").Where(Gt("amount", 100))
+	pgOuter := New().SetDialector(postgresDialector).Select("*").From("users").Where(In("id", mysqlSub))
+	if len(pgOuter.ErrList) != 1 || !errors.Is(pgOuter.ErrList[0], ErrDialectorMismatch) {
+		t.Errorf("ErrList = %v, want %v", pgOuter.ErrList, ErrDialectorMismatch)
+	}
+	want = `SELECT * FROM "users" WHERE "id" IN (SELECT "id" FROM "orders" WHERE "amount" > $1)`
+	pgSub := New().SetDialector(postgresDialector).Select("id").From("orders").Where(Gt("amount", 100))
+	if q, err = New().SetDialector(postgresDialector).Select("*").From("users").Where(In("id", pgSub)).Build(); err != nil {
+		t.Errorf("error: %s", err)
+	}
+	if want != q.Query {
+		t.Errorf("\ngot:\n%s\nwant:\n%s\n", q.Query, want)
+	}
+
+	var nilQuery *Query
+	_, err = b.Select("*").From("users").Where(In("id", nilQuery)).Build()
+	if err == nil {
+		t.Errorf("expected error for nil sub-query")
+	}
+	_, err = b.Select("*").FromSub(New(), "t").Build()
+	if err == nil {
+		t.Errorf("expected error for empty sub-query")
+	}
+	_, err = b.Select("*").From("users").Where(Exists(1)).Build()
+	if err == nil {
+		t.Errorf("expected error for invalid EXISTS value")
+	}
+}
+
+func TestLimitOffset(t *testing.T) {
+	tests := []struct {
+		name     string
+		d        Dialector
+		bind     bool
+		build    func(b *Builder) *Builder
+		want     string
+		wantArgs []interface{}
+	}{
+		{
+			name:  "mysql_limit_offset",
+			d:     mysqlDialector,
+			build: func(b *Builder) *Builder { return b.Limit(10).Offset(20) },
+			want:  "SELECT * FROM `user` LIMIT 20, 10",
+		},
+		{
+			name:  "mysql_page",
+			d:     mysqlDialector,
+			build: func(b *Builder) *Builder { return b.Page(3, 10) },
+			want:  "SELECT * FROM `user` LIMIT 20, 10",
+		},
+		{
+			name:     "mysql_bind",
+			d:        mysqlDialector,
+			bind:     true,
+			build:    func(b *Builder) *Builder { return b.Where(Eq("age", 18)).Limit(10).Offset(20) },
+			want:     "SELECT * FROM `user` WHERE `age` = ? LIMIT ?, ?",
+			wantArgs: []interface{}{18, 20, 10},
+		},
+		{
+			name:  "postgres_limit",
+			d:     postgresDialector,
+			build: func(b *Builder) *Builder { return b.Limit(20, 10) },
+			want:  `SELECT * FROM "user" LIMIT 10 OFFSET 20`,
+		},
+		{
+			name:  "postgres_offset",
+			d:     postgresDialector,
+			build: func(b *Builder) *Builder { return b.Offset(5) },
+			want:  `SELECT * FROM "user" OFFSET 5`,
+		},
+		{
+			name:     "postgres_bind_page",
+			d:        postgresDialector,
+			bind:     true,
+			build:    func(b *Builder) *Builder { return b.Where(Eq("age", 18)).Page(0, 10) },
+			want:     `SELECT * FROM "user" WHERE "age" = $1 LIMIT $2 OFFSET $3`,
+			wantArgs: []interface{}{18, 10, 0},
+		},
+		{
+			name:     "postgres_bind_offset",
+			d:        postgresDialector,
+			bind:     true,
+			build:    func(b *Builder) *Builder { return b.Limit(10).Offset(30) },
+			want:     `SELECT * FROM "user" LIMIT $1 OFFSET $2`,
+			wantArgs: []interface{}{10, 30},
+		},
+		{
+			name:  "sqlite_offset",
+			d:     sqliteDialector,
+			build: func(b *Builder) *Builder { return b.Limit().Offset(5) },
+			want:  `SELECT * FROM "user" LIMIT -1 OFFSET 5`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New().SetDialector(tt.d).SetBindLimit(tt.bind)
+			q, err := tt.build(b.Select("*").From("user")).Build()
+			if err != nil {
+				t.Errorf("error: %s", err)
+			}
+			if q.Query != tt.want {
+				t.Errorf("\ngot:\n%s\nwant:\n%s\n", q.Query, tt.want)
+			}
+			if len(tt.wantArgs) > 0 && !reflect.DeepEqual(tt.wantArgs, q.Args) {
+				t.Errorf("\ngotArgs:\n%#v\nwantArgs:\n%#v\n", q.Args, tt.wantArgs)
+			}
+		})
+	}
+	negatives := []func(b *Builder) *Builder{
+		func(b *Builder) *Builder { return b.Limit(-1) },
+		func(b *Builder) *Builder { return b.Offset(-5) },
+		func(b *Builder) *Builder { return b.Limit(-5, 10) },
+		func(b *Builder) *Builder { return b.Limit(10).Page(2, -10) },
+	}
+	for i, build := range negatives {
+		b := build(New().Select("*").From("user"))
+		if len(b.ErrList) != 1 || !errors.Is(b.ErrList[0], ErrNegativeLimit) {
+			t.Errorf("#%d ErrList = %v, want %v", i, b.ErrList, ErrNegativeLimit)
+		}
+	}
+}
+
+func TestUpsert(t *testing.T) {
+	rows := [][]interface{}{{1, "coder", 25}, {2, "hacker", 30}}
+	tests := []struct {
+		name     string
+		d        Dialector
+		build    func(b *Builder) *Builder
+		want     string
+		wantArgs []interface{}
+		wantErr  bool
+	}{
+		{
+			name: "mysql_update_columns",
+			d:    mysqlDialector,
+			build: func(b *Builder) *Builder {
+				return b.OnConflict("id").DoUpdateColumns("name", "age")
+			},
+			want:     "INSERT INTO `user` (`id`, `name`, `age`) VALUES (?, ?, ?), (?, ?, ?) ON DUPLICATE KEY UPDATE `name` = VALUES(`name`), `age` = VALUES(`age`)",
+			wantArgs: []interface{}{1, "coder", 25, 2, "hacker", 30},
+		},
+		{
+			name: "mysql_do_nothing",
+			d:    mysqlDialector,
+			build: func(b *Builder) *Builder {
+				return b.OnConflict("id").DoNothing()
+			},
+			want: "INSERT INTO `user` (`id`, `name`, `age`) VALUES (?, ?, ?), (?, ?, ?) ON DUPLICATE KEY UPDATE `id` = `id`",
+		},
+		{
+			name: "mysql_do_nothing_without_target",
+			d:    mysqlDialector,
+			build: func(b *Builder) *Builder {
+				return b.DoNothing()
+			},
+			wantErr: true,
+		},
+		{
+			name: "postgres_update",
+			d:    postgresDialector,
+			build: func(b *Builder) *Builder {
+				return b.OnConflict("id").DoUpdate(NewFV("name", Excluded("name")), NewFV("age", 18))
+			},
+			want:     `INSERT INTO "user" ("id", "name", "age") VALUES ($1, $2, $3), ($4, $5, $6) ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name", "age" = $7`,
+			wantArgs: []interface{}{1, "coder", 25, 2, "hacker", 30, 18},
+		},
+		{
+			name: "postgres_do_nothing",
+			d:    postgresDialector,
+			build: func(b *Builder) *Builder {
+				return b.DoNothing()
+			},
+			want: `INSERT INTO "user" ("id", "name", "age") VALUES ($1, $2, $3), ($4, $5, $6) ON CONFLICT DO NOTHING`,
+		},
+		{
+			name: "postgres_update_without_target",
+			d:    postgresDialector,
+			build: func(b *Builder) *Builder {
+				return b.DoUpdateColumns("name")
+			},
+			wantErr: true,
+		},
+		{
+			name: "sqlite_update",
+			d:    sqliteDialector,
+			build: func(b *Builder) *Builder {
+				return b.OnConflict("id", "name").DoUpdateColumns("age")
+			},
+			want: `INSERT INTO "user" ("id", "name", "age") VALUES (?, ?, ?), (?, ?, ?) ON CONFLICT ("id", "name") DO UPDATE SET "age" = EXCLUDED."age"`,
+		},
+		{
+			name: "postgres_target_after_update",
+			d:    postgresDialector,
+			build: func(b *Builder) *Builder {
+				return b.DoUpdateColumns("name").OnConflict("id")
+			},
+			want: `INSERT INTO "user" ("id", "name", "age") VALUES ($1, $2, $3), ($4, $5, $6) ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name"`,
+		},
+		{
+			name: "removed",
+			d:    postgresDialector,
+			build: func(b *Builder) *Builder {
+				return b.OnConflict("id").DoNothing().RemoveClause(UpsertClause)
+			},
+			want: `INSERT INTO "user" ("id", "name", "age") VALUES ($1, $2, $3), ($4, $5, $6)`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New().SetDialector(tt.d)
+			q, err := tt.build(b.Insert("user", "id", "name", "age").Values(rows...)).Build()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %s", q.Query)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("error: %s", err)
+			}
+			if q.Query != tt.want {
+				t.Errorf("\ngot:\n%s\nwant:\n%s\n", q.Query, tt.want)
+			}
+			if len(tt.wantArgs) > 0 && !reflect.DeepEqual(tt.wantArgs, q.Args) {
+				t.Errorf("\ngotArgs:\n%#v\nwantArgs:\n%#v\n", q.Args, tt.wantArgs)
+			}
+		})
+	}
+
+	// the clause is rendered on Build, with the dialect and target set by then,
+	// while the identifiers already escaped make SetDialector record ErrDialectorChanged
+	ub := New().Insert("user", "id", "hits").Values([]interface{}{1, 1}).DoUpdate(NewFV("hits", NewExpr("hits + ?", 1)))
+	ub.SetDialector(postgresDialector).OnConflict("id")
+	if len(ub.ErrList) != 1 || ub.ErrList[0] != ErrDialectorChanged {
+		t.Errorf("ErrList = %v, want %v", ub.ErrList, ErrDialectorChanged)
+	}
+	want := "INSERT INTO `user` (`id`, `hits`) VALUES ($1, $2) ON CONFLICT (\"id\") DO UPDATE SET \"hits\" = hits + $3"
+	if got := ub.Query(); got != want {
+		t.Errorf("\ngot:\n%s\nwant:\n%s\n", got, want)
+	}
+	if got, wantArgs := ub.QueryArgs(), []interface{}{1, 1, 1}; !reflect.DeepEqual(got, wantArgs) {
+		t.Errorf("\ngotArgs:\n%#v\nwantArgs:\n%#v\n", got, wantArgs)
+	}
+	q, err := ub.Build()
+	if q.Query != want {
+		t.Errorf("\ngot:\n%s\nwant:\n%s\n", q.Query, want)
+	}
+	if err != ErrListIsNotEmpty {
+		t.Errorf("error = %v, want %v", err, ErrListIsNotEmpty)
+	}
+}
+
+func TestReturning(t *testing.T) {
+	tests := []struct {
+		name     string
+		d        Dialector
+		build    func(b *Builder) *Builder
+		want     string
+		wantArgs []interface{}
+		wantErr  bool
+	}{
+		{
+			name: "postgres_insert",
+			d:    postgresDialector,
+			build: func(b *Builder) *Builder {
+				return b.Insert("user", "name").Values([]interface{}{"coder"}).Returning("id", "created_at")
+			},
+			want:     `INSERT INTO "user" ("name") VALUES ($1) RETURNING "id", "created_at"`,
+			wantArgs: []interface{}{"coder"},
+		},
+		{
+			name: "postgres_upsert",
+			d:    postgresDialector,
+			build: func(b *Builder) *Builder {
+				return b.Insert("user", "id", "name").Values([]interface{}{1, "coder"}).
+					OnConflict("id").DoUpdateColumns("name").Returning("*")
+			},
+			want: `INSERT INTO "user" ("id", "name") VALUES ($1, $2) ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name" RETURNING *`,
+		},
+		{
+			name: "postgres_update",
+			d:    postgresDialector,
+			build: func(b *Builder) *Builder {
+				return b.Update("user", NewFV("age", 26)).Where(Eq("id", 1)).Returning("id", "age")
+			},
+			want:     `UPDATE "user" SET "age" = $1 WHERE "id" = $2 RETURNING "id", "age"`,
+			wantArgs: []interface{}{26, 1},
+		},
+		{
+			name: "sqlite_delete",
+			d:    sqliteDialector,
+			build: func(b *Builder) *Builder {
+				return b.Delete("user").Where(Eq("id", 1)).Returning("*")
+			},
+			want: `DELETE FROM "user" WHERE "id" = ? RETURNING *`,
+		},
+		{
+			name: "mysql_unsupported",
+			d:    mysqlDialector,
+			build: func(b *Builder) *Builder {
+				return b.Delete("user").Where(Eq("id", 1)).Returning("id")
+			},
+			wantErr: true,
+		},
+		{
+			name: "select_invalid",
+			d:    postgresDialector,
+			build: func(b *Builder) *Builder {
+				return b.Select("*").From("user").Returning("id")
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New().SetDialector(tt.d)
+			q, err := tt.build(b).Build()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %s", q.Query)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("error: %s", err)
+			}
+			if q.Query != tt.want {
+				t.Errorf("\ngot:\n%s\nwant:\n%s\n", q.Query, tt.want)
+			}
+			if len(tt.wantArgs) > 0 && !reflect.DeepEqual(tt.wantArgs, q.Args) {
+				t.Errorf("\ngotArgs:\n%#v\nwantArgs:\n%#v\n", q.Args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestClone(t *testing.T) {
+	var (
+		got, want      string
+		args, wantArgs []interface{}
+		err            error
+		q              *Query
+	)
+
+	base := New().SetDialector(postgresDialector).Select("*").From("user").Where(Eq("status", "active"))
+	base.Limit(10)
+
+	want = `SELECT * FROM "user" WHERE "status" = $1 LIMIT 10 OFFSET 20`
+	q, err = base.Clone().Offset(20).Build()
+	if err != nil {
+		t.Errorf("error: %s", err)
+	}
+	if got = q.Query; want != got {
+		t.Errorf("\ngot:\n%s\nwant:\n%s\n", got, want)
+	}
+
+	base = New().SetDialector(postgresDialector).Select("*").From("user").Where(Eq("status", "active"))
+	admins := base.Clone().And(Eq("role", "admin"))
+	q1, _ := base.Clone().And(Eq("age", 18)).Build()
+
+	want = `SELECT * FROM "user" WHERE "status" = $1 AND "role" = $2`
+	wantArgs = []interface{}{"active", "admin"}
+	q, err = admins.Build()
+	got = q.Query
+	args = q.Args
+	if err != nil {
+		t.Errorf("error: %s", err)
+	}
+	if want != got {
+		t.Errorf("\ngot:\n%s\nwant:\n%s\n", got, want)
+	}
+	if !reflect.DeepEqual(wantArgs, args) {
+		t.Errorf("\ngotArgs:\n%#v\nwantArgs:\n%#v\n", args, wantArgs)
+	}
+
+	// the base and previously built queries are left untouched
+	want = `SELECT * FROM "user" WHERE "status" = $1`
+	if got = base.Query(); want != got {
+		t.Errorf("\ngot:\n%s\nwant:\n%s\n", got, want)
+	}
+	if wantArgs = []interface{}{"active", 18}; !reflect.DeepEqual(wantArgs, q1.Args) {
+		t.Errorf("\ngotArgs:\n%#v\nwantArgs:\n%#v\n", q1.Args, wantArgs)
+	}
+	if len(base.LastQueries()) != 0 || len(admins.LastQueries()) != 1 {
+		t.Errorf("unexpected last queries: %d, %d", len(base.LastQueries()), len(admins.LastQueries()))
+	}
+
+	invalid := New().Select("*").From("user").Where(errOpCond)
+	if _, err = invalid.Clone().Build(); err == nil {
+		t.Errorf("expected error from cloned ErrList")
+	}
+}
+
+// TestClone_Concurrent derives queries from a shared base in several goroutines,
+// run it with -race to detect data races.
+func TestClone_Concurrent(t *testing.T) {
+	base := New().SetDialector(postgresDialector).Select("*").From("user").Where(Eq("status", "active"))
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 50)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			q, err := base.Clone().And(Eq("id", i)).Page(i+1, 10).Build()
+			if err != nil {
+				errs <- err
+				return
+			}
+			want := `SELECT * FROM "user" WHERE "status" = $1 AND "id" = $2 LIMIT 10 OFFSET ` + strconv.Itoa(i*10)
+			if q.Query != want || !reflect.DeepEqual(q.Args, []interface{}{"active", i}) {
+				errs <- fmt.Errorf("got %s %v, want %s", q.Query, q.Args, want)
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestClauseOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		build    func(b *Builder) *Builder
+		want     string
+		wantArgs []interface{}
+	}{
+		{
+			name: "any_order",
+			build: func(b *Builder) *Builder {
+				return b.Limit(10).OrderBy(ageDesc).Where(Eq("status", "active")).From("user").Select("id", "name")
+			},
+			want:     "SELECT `id`, `name` FROM `user` WHERE `status` = ? ORDER BY `age` DESC LIMIT 10",
+			wantArgs: []interface{}{"active"},
+		},
+		{
+			name: "args_in_sql_order",
+			build: func(b *Builder) *Builder {
+				return b.Update("user").Where(Eq("id", 1)).Set(NewFV("name", "coder")).Set(NewFV("age", 25))
+			},
+			want:     "UPDATE `user` SET `name` = ?, `age` = ? WHERE `id` = ?",
+			wantArgs: []interface{}{"coder", 25, 1},
+		},
+		{
+			name: "replace",
+			build: func(b *Builder) *Builder {
+				return b.Select("*").From("user").Where(Eq("id", 1)).OrderBy(ageDesc).Limit(10).
+					Where(Eq("name", "coder")).OrderBy(nameAsc).Limit(5).From("member")
+			},
+			want:     "SELECT * FROM `member` WHERE `name` = ? ORDER BY `name` ASC LIMIT 5",
+			wantArgs: []interface{}{"coder"},
+		},
+		{
+			name: "remove",
+			build: func(b *Builder) *Builder {
+				return b.Select("*").From("user").Where(Eq("id", 1)).OrderBy(ageDesc).Limit(10).
+					RemoveClause(WhereClause, LimitClause).OrderBy()
+			},
+			want:     "SELECT * FROM `user`",
+			wantArgs: []interface{}{},
+		},
+		{
+			name: "and_begins_where",
+			build: func(b *Builder) *Builder {
+				return b.Select("*").From("user").And(Eq("id", 1)).Or(nameEqCoder, sexEqFemale)
+			},
+			want:     "SELECT * FROM `user` WHERE `id` = ? OR (`name` = ? OR `sex` = ?)",
+			wantArgs: []interface{}{1, "coder", "female"},
+		},
+		{
+			name: "in_begins_where",
+			build: func(b *Builder) *Builder {
+				return b.Select("*").From("user").In("id", 1, 2)
+			},
+			want:     "SELECT * FROM `user` WHERE `id` IN (?, ?)",
+			wantArgs: []interface{}{1, 2},
+		},
+		{
+			name: "offset_before_limit",
+			build: func(b *Builder) *Builder {
+				return b.Select("*").From("user").Offset(20).Limit(10)
+			},
+			want:     "SELECT * FROM `user` LIMIT 20, 10",
+			wantArgs: []interface{}{},
+		},
+		{
+			name: "values_rows",
+			build: func(b *Builder) *Builder {
+				return b.Values([]interface{}{1, "coder"}).Insert("user", "id", "name").Values([]interface{}{2, "hacker"})
+			},
+			want:     "INSERT INTO `user` (`id`, `name`) VALUES (?, ?), (?, ?)",
+			wantArgs: []interface{}{1, "coder", 2, "hacker"},
+		},
+		{
+			name: "append_to_current_clause",
+			build: func(b *Builder) *Builder {
+				return b.Select("*").Where(Eq("id", 1)).Append(" OR `id` = ?", 2).From("user")
+			},
+			want:     "SELECT * FROM `user` WHERE `id` = ? OR `id` = ?",
+			wantArgs: []interface{}{1, 2},
+		},
+		{
+			name: "append_before_where",
+			build: func(b *Builder) *Builder {
+				return b.Update("user", NewKV("k", "v")).Set(NewFV("f", "v")).Append(", `x` = ?", 1).Where(Eq("id", 9))
+			},
+			want:     "UPDATE `user` SET `k` = ?, `f` = ?, `x` = ? WHERE `id` = ?",
+			wantArgs: []interface{}{"v", "v", 1, 9},
+		},
+		{
+			name: "append_conjunction",
+			build: func(b *Builder) *Builder {
+				return b.Select("*").From("user").Where(Eq("a", 1)).Append(" OR").In("b", 2).Append(" and ").Between("c", 3, 4)
+			},
+			want:     "SELECT * FROM `user` WHERE `a` = ? OR `b` IN (?) and `c` BETWEEN ? AND ?",
+			wantArgs: []interface{}{1, 2, 3, 4},
+		},
+		{
+			name: "append_upsert",
+			build: func(b *Builder) *Builder {
+				return b.Insert("user", "id", "name").Values([]interface{}{1, "coder"}).
+					Append(" ON DUPLICATE KEY UPDATE ").Set(NewFV("name", "coder"), NewFV("age", 18))
+			},
+			want:     "INSERT INTO `user` (`id`, `name`) VALUES (?, ?) ON DUPLICATE KEY UPDATE `name` = ?, `age` = ?",
+			wantArgs: []interface{}{1, "coder", "coder", 18},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := tt.build(New()).Build()
+			if err != nil {
+				t.Errorf("error: %s", err)
+			}
+			if q.Query != tt.want {
+				t.Errorf("\ngot:\n%s\nwant:\n%s\n", q.Query, tt.want)
+			}
+			if !reflect.DeepEqual(tt.wantArgs, q.Args) {
+				t.Errorf("\ngotArgs:\n%#v\nwantArgs:\n%#v\n", q.Args, tt.wantArgs)
+			}
+		})
+	}
+
+	// appended text cannot be moved before a clause that is already set
+	b := New().Select("*").From("user").Limit(10).Where(Eq("id", 1)).Append(" FOR UPDATE")
+	if len(b.ErrList) != 1 || b.ErrList[0] != ErrMisplacedAppend {
+		t.Errorf("ErrList = %v, want %v", b.ErrList, ErrMisplacedAppend)
+	}
+}
+
+func TestHasClause(t *testing.T) {
+	b := New().Select("*").From("user").Where(Eq("id", 1))
+	if !b.HasClause(FromClause) || !b.HasClause(WhereClause) {
+		t.Errorf("HasClause() = false for a set clause")
+	}
+	if b.HasClause(OrderByClause) || b.HasClause(clauseCount) {
+		t.Errorf("HasClause() = true for a missing clause")
+	}
+	if b.RemoveClause(WhereClause).HasClause(WhereClause) {
+		t.Errorf("HasClause() = true for a removed clause")
+	}
+
+	// a new statement starts from scratch once the previous one was begun
+	b.Select("id")
+	if b.HasClause(FromClause) {
+		t.Errorf("HasClause() = true after starting a new statement")
+	}
+}
+
+func TestNullConditions(t *testing.T) {
+	var nilTime *time.Time
+	tests := []struct {
+		name      string
+		d         Dialector
+		nilAsNull bool
+		where     []*Condition
+		want      string
+		wantArgs  []interface{}
+		wantErr   bool
+	}{
+		{
+			name:     "is_null",
+			d:        mysqlDialector,
+			where:    []*Condition{IsNull("deleted_at"), IsNotNull("u.email")},
+			want:     "SELECT * FROM `user` WHERE `deleted_at` IS NULL OR `u`.`email` IS NOT NULL",
+			wantArgs: []interface{}{},
+		},
+		{
+			name:     "eq_nil_bound_by_default",
+			d:        mysqlDialector,
+			where:    []*Condition{Eq("deleted_at", nil)},
+			want:     "SELECT * FROM `user` WHERE `deleted_at` = ?",
+			wantArgs: []interface{}{nil},
+		},
+		{
+			name:      "nil_as_null",
+			d:         postgresDialector,
+			nilAsNull: true,
+			where:     []*Condition{Eq("deleted_at", nil), And("banned_at", "<>", nilTime), NotEq("name", nil), Eq("age", 18)},
+			want:      `SELECT * FROM "user" WHERE "deleted_at" IS NULL AND "banned_at" IS NOT NULL OR "name" IS NOT NULL OR "age" = $1`,
+			wantArgs:  []interface{}{18},
+		},
+		{
+			name:     "distinct_from_postgres",
+			d:        postgresDialector,
+			where:    []*Condition{IsDistinctFrom("manager_id", 1), And("a", "IS NOT DISTINCT FROM", Column("b"))},
+			want:     `SELECT * FROM "user" WHERE "manager_id" IS DISTINCT FROM $1 AND "a" IS NOT DISTINCT FROM "b"`,
+			wantArgs: []interface{}{1},
+		},
+		{
+			name:     "distinct_from_mysql",
+			d:        mysqlDialector,
+			where:    []*Condition{IsDistinctFrom("manager_id", 1), IsNotDistinctFrom("boss_id", nil)},
+			want:     "SELECT * FROM `user` WHERE NOT (`manager_id` <=> ?) OR `boss_id` <=> ?",
+			wantArgs: []interface{}{1, nil},
+		},
+		{
+			name:     "distinct_from_sqlite",
+			d:        sqliteDialector,
+			where:    []*Condition{IsNotDistinctFrom("manager_id", 1)},
+			want:     `SELECT * FROM "user" WHERE "manager_id" IS ?`,
+			wantArgs: []interface{}{1},
+		},
+		{
+			name:    "is_null_with_values",
+			d:       mysqlDialector,
+			where:   []*Condition{And("deleted_at", "IS NULL", 1)},
+			want:    "SELECT * FROM `user` WHERE {error: invalid number of values with operator:(IS NULL[field:deleted_at])}",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New().SetDialector(tt.d).SetNilAsNull(tt.nilAsNull)
+			q, err := b.Select("*").From("user").Where(tt.where...).Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if q.Query != tt.want {
+				t.Errorf("\ngot:\n%s\nwant:\n%s\n", q.Query, tt.want)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(tt.wantArgs, q.Args) {
+				t.Errorf("\ngotArgs:\n%#v\nwantArgs:\n%#v\n", q.Args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestCompositeConditions(t *testing.T) {
+	tests := []struct {
+		name     string
+		build    func(b *Builder) *Builder
+		want     string
+		wantArgs []interface{}
+		wantErr  bool
+	}{
+		{
+			name: "nested",
+			build: func(b *Builder) *Builder {
+				return b.Where(AllOf(
+					AnyOf(Eq("a", 1), Eq("b", 2)),
+					AnyOf(Eq("c", 3), AllOf(Eq("d", 4), Eq("e", 5))),
+				))
+			},
+			want:     "SELECT * FROM `user` WHERE ((`a` = ? OR `b` = ?) AND (`c` = ? OR (`d` = ? AND `e` = ?)))",
+			wantArgs: []interface{}{1, 2, 3, 4, 5},
+		},
+		{
+			name: "listed_with_other_conditions",
+			build: func(b *Builder) *Builder {
+				return b.Where(Eq("status", "active"), AnyOf(Eq("role", "admin"), Gt("age", 18))).
+					Or(Not(AllOf(IsNull("deleted_at"), Eq("sex", "female"))))
+			},
+			want:     "SELECT * FROM `user` WHERE `status` = ? AND (`role` = ? OR `age` > ?) OR NOT (`deleted_at` IS NULL AND `sex` = ?)",
+			wantArgs: []interface{}{"active", "admin", 18, "female"},
+		},
+		{
+			name: "not",
+			build: func(b *Builder) *Builder {
+				return b.Where(Not(Eq("a", 1)), Not(Not(In("b", 1, 2))), Not(nil))
+			},
+			want:    "SELECT * FROM `user` WHERE NOT (`a` = ?) AND NOT (NOT (`b` IN (?, ?))) AND {error: empty condition: no conditions with operator:(NOT)}",
+			wantErr: true,
+		},
+		{
+			name: "having_and_join",
+			build: func(b *Builder) *Builder {
+				return b.Join("orders o", AllOf(On("user.id", "=", "o.user_id"), AnyOf(Eq("o.status", "paid"), Eq("o.status", "sent")))).
+					GroupBy("user.id").Having(AnyOf(Gt("COUNT(*)", 10), Gt("SUM(o.amount)", 100)))
+			},
+			want:     "SELECT * FROM `user` INNER JOIN `orders` AS `o` ON (`user`.`id` = `o`.`user_id` AND (`o`.`status` = ? OR `o`.`status` = ?)) GROUP BY `user`.`id` HAVING (COUNT(*) > ? OR SUM(o.amount) > ?)",
+			wantArgs: []interface{}{"paid", "sent", 10, 100},
+		},
+		{
+			name: "nested_error",
+			build: func(b *Builder) *Builder {
+				return b.Where(AnyOf(Eq("a", 1), errOpCond))
+			},
+			want:    "SELECT * FROM `user` WHERE {error: invalid operator:(operator:![field:test])}",
+			wantErr: true,
+		},
+		{
+			name: "empty",
+			build: func(b *Builder) *Builder {
+				return b.Where(AllOf(nil))
+			},
+			want:    "SELECT * FROM `user` WHERE {error: empty condition: no conditions with operator:(AND)}",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := tt.build(New().Select("*").From("user")).Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if q.Query != tt.want {
+				t.Errorf("\ngot:\n%s\nwant:\n%s\n", q.Query, tt.want)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(tt.wantArgs, q.Args) {
+				t.Errorf("\ngotArgs:\n%#v\nwantArgs:\n%#v\n", q.Args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestExpr(t *testing.T) {
+	tests := []struct {
+		name     string
+		d        Dialector
+		build    func(b *Builder) *Builder
+		want     string
+		wantArgs []interface{}
+	}{
+		{
+			name: "select_aliases",
+			d:    mysqlDialector,
+			build: func(b *Builder) *Builder {
+				return b.SelectExpr("users.id", "users.name as author", NewExpr("COUNT(*)").As("posts"), NewExpr("MAX(p.created_at)").As("last_post")).
+					From("users").Join("posts p", On("p.user_id", "=", "users.id")).GroupBy("users.id", "users.name")
+			},
+			want:     "SELECT `users`.`id`, `users`.`name` AS `author`, COUNT(*) AS `posts`, MAX(p.created_at) AS `last_post` FROM `users` INNER JOIN `posts` AS `p` ON `p`.`user_id` = `users`.`id` GROUP BY `users`.`id`, `users`.`name`",
+			wantArgs: []interface{}{},
+		},
+		{
+			name: "select_cast_alias",
+			d:    mysqlDialector,
+			build: func(b *Builder) *Builder {
+				return b.Select("CAST(x AS INT)", "CAST(y AS INT) AS z").From("t")
+			},
+			want:     "SELECT `CAST(x AS INT)`, `CAST(y AS INT)` AS `z` FROM `t`",
+			wantArgs: []interface{}{},
+		},
+		{
+			name: "select_expr",
+			d:    postgresDialector,
+			build: func(b *Builder) *Builder {
+				return b.SelectExpr("id", NewExpr("price * qty").As("total"), NewExpr("price > ?", 100), Column("orders.status")).
+					From("orders").Where(Eq("id", 1))
+			},
+			want:     `SELECT "id", price * qty AS "total", price > $1, "orders"."status" FROM "orders" WHERE "id" = $2`,
+			wantArgs: []interface{}{100, 1},
+		},
+		{
+			name: "set_expr",
+			d:    postgresDialector,
+			build: func(b *Builder) *Builder {
+				return b.Update("posts", NewFV("views", NewExpr("views + ?", 1)), NewFV("title", "go")).
+					Where(Eq("id", 7))
+			},
+			want:     `UPDATE "posts" SET "views" = views + $1, "title" = $2 WHERE "id" = $3`,
+			wantArgs: []interface{}{1, "go", 7},
+		},
+		{
+			name: "condition_expr",
+			d:    mysqlDialector,
+			build: func(b *Builder) *Builder {
+				return b.Select("*").From("orders").Where(Gt("total", NewExpr("price * ?", 2)), And("created_at", ">", NewExpr("NOW() - INTERVAL 1 DAY")))
+			},
+			want:     "SELECT * FROM `orders` WHERE `total` > price * ? AND `created_at` > NOW() - INTERVAL 1 DAY",
+			wantArgs: []interface{}{2},
+		},
+		{
+			name: "order_by_expr",
+			d:    postgresDialector,
+			build: func(b *Builder) *Builder {
+				return b.OrderBy(DescExpr(NewExpr("FIELD(status, ?, ?)", "active", "pending")), AscExpr(NewExpr("COUNT(*)")), Desc("users.id")).
+					Limit(10).Select("*").From("users").Where(Eq("age", 18)).SetBindLimit(false)
+			},
+			want:     `SELECT * FROM "users" WHERE "age" = $1 ORDER BY FIELD(status, $2, $3) DESC, COUNT(*) ASC, "users"."id" DESC LIMIT 10`,
+			wantArgs: []interface{}{18, "active", "pending"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := tt.build(New().SetDialector(tt.d)).Build()
+			if err != nil {
+				t.Errorf("error: %s", err)
+			}
+			if q.Query != tt.want {
+				t.Errorf("\ngot:\n%s\nwant:\n%s\n", q.Query, tt.want)
+			}
+			if !reflect.DeepEqual(tt.wantArgs, q.Args) {
+				t.Errorf("\ngotArgs:\n%#v\nwantArgs:\n%#v\n", q.Args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestAliases(t *testing.T) {
+	tests := []struct {
+		name     string
+		d        Dialector
+		build    func(b *Builder) *Builder
+		want     string
+		wantArgs []interface{}
+	}{
+		{
+			name: "select_from",
+			d:    mysqlDialector,
+			build: func(b *Builder) *Builder {
+				return b.Select("u.id AS user_id", "u.name", "p.*").From("users AS u", "app.posts p").
+					Where(Eq("u.id", 1), And("p.user_id", "=", Column("u.id")))
+			},
+			want:     "SELECT `u`.`id` AS `user_id`, `u`.`name`, `p`.* FROM `users` AS `u`, `app`.`posts` AS `p` WHERE `u`.`id` = ? AND `p`.`user_id` = `u`.`id`",
+			wantArgs: []interface{}{1},
+		},
+		{
+			name: "from_join",
+			d:    postgresDialector,
+			build: func(b *Builder) *Builder {
+				return b.Select("u.name AS author", "c.body").From("users u").
+					LeftJoin("comments as c", On("c.user_id", "=", "u.id")).Where(Gt("c.id", 10))
+			},
+			want:     `SELECT "u"."name" AS "author", "c"."body" FROM "users" AS "u" LEFT JOIN "comments" AS "c" ON "c"."user_id" = "u"."id" WHERE "c"."id" > $1`,
+			wantArgs: []interface{}{10},
+		},
+		{
+			name: "update",
+			d:    postgresDialector,
+			build: func(b *Builder) *Builder {
+				return b.Update("users AS u", NewFV("name", "coder")).Where(Eq("u.id", 1))
+			},
+			want:     `UPDATE "users" AS "u" SET "name" = $1 WHERE "u"."id" = $2`,
+			wantArgs: []interface{}{"coder", 1},
+		},
+		{
+			name: "delete",
+			d:    sqliteDialector,
+			build: func(b *Builder) *Builder {
+				return b.Delete("sessions s").Where(Lt("s.expires_at", 100))
+			},
+			want:     `DELETE FROM "sessions" AS "s" WHERE "s"."expires_at" < ?`,
+			wantArgs: []interface{}{100},
+		},
+		{
+			name: "escaped_alias",
+			d:    mysqlDialector,
+			build: func(b *Builder) *Builder {
+				return b.Select("u`x.id AS a`b").From("users u`x")
+			},
+			want:     "SELECT `u``x`.`id` AS `a``b` FROM `users` AS `u``x`",
+			wantArgs: []interface{}{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := tt.build(New().SetDialector(tt.d)).Build()
+			if err != nil {
+				t.Errorf("error: %s", err)
+			}
+			if q.Query != tt.want {
+				t.Errorf("\ngot:\n%s\nwant:\n%s\n", q.Query, tt.want)
+			}
+			if !reflect.DeepEqual(tt.wantArgs, q.Args) {
+				t.Errorf("\ngotArgs:\n%#v\nwantArgs:\n%#v\n", q.Args, tt.wantArgs)
+			}
+		})
+	}
+
+	b := New().SetIdentifierPattern(DefaultIdentifierPattern)
+	if _, err := b.Select("*").From("users u; DROP TABLE users").Build(); err != ErrListIsNotEmpty {
+		t.Errorf("error = %v, want %v", err, ErrListIsNotEmpty)
+	}
+}
+
+func TestSetOperations(t *testing.T) {
+	tests := []struct {
+		name     string
+		d        Dialector
+		build    func(b *Builder) *Builder
+		want     string
+		wantArgs []interface{}
+		wantErr  error
+	}{
+		{
+			name: "union",
+			d:    mysqlDialector,
+			build: func(b *Builder) *Builder {
+				archived := New().Select("id", "name").From("archived_users").Where(Eq("status", 2))
+				return b.Select("id", "name").From("users").Where(Eq("status", 1)).Union(archived).
+					OrderBy(Asc("name")).Limit(10)
+			},
+			want:     "SELECT `id`, `name` FROM `users` WHERE `status` = ? UNION SELECT `id`, `name` FROM `archived_users` WHERE `status` = ? ORDER BY `name` ASC LIMIT 10",
+			wantArgs: []interface{}{1, 2},
+		},
+		{
+			name: "union_all_renumbered",
+			d:    postgresDialector,
+			build: func(b *Builder) *Builder {
+				q2, _ := New().SetDialector(postgresDialector).Select("id").From("orders").Where(Gt("amount", 100), And("status", "=", "paid")).Build()
+				q3 := New().SetDialector(postgresDialector).Select("id").From("refunds").Where(Eq("status", "done"))
+				return b.SetBindLimit(true).Select("id").From("payments").Where(Eq("user_id", 7)).UnionAll(q2, q3).
+					OrderBy(Desc("id")).Limit(5).Offset(10)
+			},
+			want:     `SELECT "id" FROM "payments" WHERE "user_id" = $1 UNION ALL SELECT "id" FROM "orders" WHERE "amount" > $2 AND "status" = $3 UNION ALL SELECT "id" FROM "refunds" WHERE "status" = $4 ORDER BY "id" DESC LIMIT $5 OFFSET $6`,
+			wantArgs: []interface{}{7, 100, "paid", "done", 5, 10},
+		},
+		{
+			name: "intersect_except",
+			d:    postgresDialector,
+			build: func(b *Builder) *Builder {
+				return b.Select("user_id").From("orders").
+					Intersect(New().SetDialector(postgresDialector).Select("user_id").From("payments")).
+					Except(New().SetDialector(postgresDialector).Select("user_id").From("banned").Where(Eq("active", true)))
+			},
+			want:     `SELECT "user_id" FROM "orders" INTERSECT SELECT "user_id" FROM "payments" EXCEPT SELECT "user_id" FROM "banned" WHERE "active" = $1`,
+			wantArgs: []interface{}{true},
+		},
+		{
+			name: "parenthesized_operand",
+			d:    mysqlDialector,
+			build: func(b *Builder) *Builder {
+				latest := New().Select("id").From("posts").OrderBy(Desc("created_at")).Limit(3)
+				return b.Select("id").From("pinned_posts").Union(latest)
+			},
+			want:     "SELECT `id` FROM `pinned_posts` UNION (SELECT `id` FROM `posts` ORDER BY `created_at` DESC LIMIT 3)",
+			wantArgs: []interface{}{},
+		},
+		{
+			name: "sqlite_except",
+			d:    sqliteDialector,
+			build: func(b *Builder) *Builder {
+				return b.Select("id").From("users").Except(New().SetDialector(sqliteDialector).Select("user_id").From("bans").Where(Lt("until", 100)))
+			},
+			want:     `SELECT "id" FROM "users" EXCEPT SELECT "user_id" FROM "bans" WHERE "until" < ?`,
+			wantArgs: []interface{}{100},
+		},
+		{
+			name: "mysql_intersect",
+			d:    mysqlDialector,
+			build: func(b *Builder) *Builder {
+				return b.Select("user_id").From("orders").Intersect(New().Select("user_id").From("payments"))
+			},
+			want:     "SELECT `user_id` FROM `orders`",
+			wantArgs: []interface{}{},
+			wantErr:  ErrNotSupported,
+		},
+		{
+			name: "empty_operand",
+			d:    mysqlDialector,
+			build: func(b *Builder) *Builder {
+				return b.Select("id").From("users").Union((*Builder)(nil))
+			},
+			want:     "SELECT `id` FROM `users`",
+			wantArgs: []interface{}{},
+			wantErr:  ErrEmptySubquery,
+		},
+		{
+			name: "not_select",
+			d:    mysqlDialector,
+			build: func(b *Builder) *Builder {
+				return b.Delete("users").Union(New().Select("id").From("users"))
+			},
+			want:     "DELETE FROM `users`",
+			wantArgs: []interface{}{},
+			wantErr:  ErrListIsNotEmpty,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := tt.build(New().SetDialector(tt.d))
+			errs := append([]error{}, b.ErrList...)
+			q, err := b.Build()
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("error: %s", err)
+			}
+			if tt.wantErr != nil && (len(errs) != 1 || (tt.wantErr != ErrListIsNotEmpty && !errors.Is(errs[0], tt.wantErr))) {
+				t.Errorf("ErrList = %v, want %v", errs, tt.wantErr)
+			}
+			if q.Query != tt.want {
+				t.Errorf("\ngot:\n%s\nwant:\n%s\n", q.Query, tt.want)
+			}
+			if !reflect.DeepEqual(tt.wantArgs, q.Args) {
+				t.Errorf("\ngotArgs:\n%#v\nwantArgs:\n%#v\n", q.Args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestWith(t *testing.T) {
+	tests := []struct {
+		name     string
+		d        Dialector
+		build    func(b *Builder) *Builder
+		want     string
+		wantArgs []interface{}
+		wantErr  error
+	}{
+		{
+			name: "select",
+			d:    mysqlDialector,
+			build: func(b *Builder) *Builder {
+				paid := New().SelectExpr("user_id", NewExpr("SUM(amount)").As("total")).From("orders").Where(Eq("status", "paid")).GroupBy("user_id")
+				return b.Select("*").From("totals").Where(Gt("total", 100)).With("totals", nil, paid)
+			},
+			want:     "WITH `totals` AS (SELECT `user_id`, SUM(amount) AS `total` FROM `orders` WHERE `status` = ? GROUP BY `user_id`) SELECT * FROM `totals` WHERE `total` > ?",
+			wantArgs: []interface{}{"paid", 100},
+		},
+		{
+			name: "recursive",
+			d:    postgresDialector,
+			build: func(b *Builder) *Builder {
+				root := New().SetDialector(postgresDialector).Select("id", "parent_id").From("categories").Where(Eq("id", 1))
+				children := New().SetDialector(postgresDialector).Select("c.id", "c.parent_id").From("categories c").
+					Join("tree t", On("c.parent_id", "=", "t.id")).Where(Eq("c.visible", true))
+				return b.WithRecursive("tree", []string{"id", "parent_id"}, root.UnionAll(children)).
+					Select("id").From("tree").Where(NotEq("id", 1))
+			},
+			want:     `WITH RECURSIVE "tree" ("id", "parent_id") AS (SELECT "id", "parent_id" FROM "categories" WHERE "id" = $1 UNION ALL SELECT "c"."id", "c"."parent_id" FROM "categories" AS "c" INNER JOIN "tree" AS "t" ON "c"."parent_id" = "t"."id" WHERE "c"."visible" = $2) SELECT "id" FROM "tree" WHERE "id" != $3`,
+			wantArgs: []interface{}{1, true, 1},
+		},
+		{
+			name: "multiple_update",
+			d:    postgresDialector,
+			build: func(b *Builder) *Builder {
+				q, _ := New().SetDialector(postgresDialector).Select("id").From("users").Where(Lt("last_login", 100)).Build()
+				return b.Update("accounts", NewFV("status", "inactive")).Where(In("user_id", Column("stale.id"))).
+					With("stale", nil, q).
+					With("admins", []string{"id"}, New().SetDialector(postgresDialector).Select("user_id").From("roles").Where(Eq("role", "admin")))
+			},
+			want:     `WITH "stale" AS (SELECT "id" FROM "users" WHERE "last_login" < $1), "admins" ("id") AS (SELECT "user_id" FROM "roles" WHERE "role" = $2) UPDATE "accounts" SET "status" = $3 WHERE "user_id" IN ("stale"."id")`,
+			wantArgs: []interface{}{100, "admin", "inactive"},
+		},
+		{
+			name: "delete",
+			d:    sqliteDialector,
+			build: func(b *Builder) *Builder {
+				expired := New().SetDialector(sqliteDialector).Select("id").From("sessions").Where(Lt("expires_at", 100))
+				return b.With("expired", nil, expired).Delete("tokens").Where(In("session_id", New().SetDialector(sqliteDialector).Select("id").From("expired")))
+			},
+			want:     `WITH "expired" AS (SELECT "id" FROM "sessions" WHERE "expires_at" < ?) DELETE FROM "tokens" WHERE "session_id" IN (SELECT "id" FROM "expired")`,
+			wantArgs: []interface{}{100},
+		},
+		{
+			name: "insert",
+			d:    postgresDialector,
+			build: func(b *Builder) *Builder {
+				return b.With("defaults", nil, New().SetDialector(postgresDialector).Select("name").From("templates").Where(Eq("id", 3))).
+					Insert("users", "name").Values([]interface{}{"coder"})
+			},
+			want:     `WITH "defaults" AS (SELECT "name" FROM "templates" WHERE "id" = $1) INSERT INTO "users" ("name") VALUES ($2)`,
+			wantArgs: []interface{}{3, "coder"},
+		},
+		{
+			name: "empty_query",
+			d:    mysqlDialector,
+			build: func(b *Builder) *Builder {
+				return b.With("t", nil, nil).Select("*").From("t")
+			},
+			want:     "SELECT * FROM `t`",
+			wantArgs: []interface{}{},
+			wantErr:  ErrEmptySubquery,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := tt.build(New().SetDialector(tt.d))
+			errs := append([]error{}, b.ErrList...)
+			q, err := b.Build()
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("error: %s", err)
+			}
+			if tt.wantErr != nil && (len(errs) != 1 || !errors.Is(errs[0], tt.wantErr)) {
+				t.Errorf("ErrList = %v, want %v", errs, tt.wantErr)
+			}
+			if q.Query != tt.want {
+				t.Errorf("\ngot:\n%s\nwant:\n%s\n", q.Query, tt.want)
+			}
+			if !reflect.DeepEqual(tt.wantArgs, q.Args) {
+				t.Errorf("\ngotArgs:\n%#v\nwantArgs:\n%#v\n", q.Args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestLocking(t *testing.T) {
+	tests := []struct {
+		name     string
+		d        Dialector
+		build    func(b *Builder) *Builder
+		want     string
+		wantArgs []interface{}
+		wantErr  error
+	}{
+		{
+			name: "for_update_skip_locked",
+			d:    mysqlDialector,
+			build: func(b *Builder) *Builder {
+				return b.Select("*").From("jobs").Where(Eq("status", "pending")).OrderBy(Asc("id")).Limit(10).ForUpdate().SkipLocked()
+			},
+			want:     "SELECT * FROM `jobs` WHERE `status` = ? ORDER BY `id` ASC LIMIT 10 FOR UPDATE SKIP LOCKED",
+			wantArgs: []interface{}{"pending"},
+		},
+		{
+			name: "for_share_of_nowait",
+			d:    postgresDialector,
+			build: func(b *Builder) *Builder {
+				return b.ForShare("u").NoWait().Select("u.id").From("users u").Join("orders o", On("o.user_id", "=", "u.id")).
+					Where(Eq("o.id", 3))
+			},
+			want:     `SELECT "u"."id" FROM "users" AS "u" INNER JOIN "orders" AS "o" ON "o"."user_id" = "u"."id" WHERE "o"."id" = $1 FOR SHARE OF "u" NOWAIT`,
+			wantArgs: []interface{}{3},
+		},
+		{
+			name: "replaced_and_removed",
+			d:    postgresDialector,
+			build: func(b *Builder) *Builder {
+				b.Select("id").From("jobs").ForShare().NoWait().ForUpdate("jobs")
+				if b.Clone().RemoveClause(LockClause).NoWait().HasClause(LockClause) {
+					t.Error("NoWait after RemoveClause(LockClause) added a locking clause")
+				}
+				return b
+			},
+			want:     `SELECT "id" FROM "jobs" FOR UPDATE OF "jobs"`,
+			wantArgs: []interface{}{},
+		},
+		{
+			name: "sqlite",
+			d:    sqliteDialector,
+			build: func(b *Builder) *Builder {
+				return b.Select("*").From("jobs").ForUpdate().SkipLocked()
+			},
+			want:     `SELECT * FROM "jobs"`,
+			wantArgs: []interface{}{},
+			wantErr:  ErrNotSupported,
+		},
+		{
+			name: "wait_without_lock",
+			d:    mysqlDialector,
+			build: func(b *Builder) *Builder {
+				return b.Select("*").From("jobs").NoWait()
+			},
+			want:     "SELECT * FROM `jobs`",
+			wantArgs: []interface{}{},
+			wantErr:  ErrListIsNotEmpty,
+		},
+		{
+			name: "not_select",
+			d:    mysqlDialector,
+			build: func(b *Builder) *Builder {
+				return b.Update("jobs", NewFV("status", "done")).ForUpdate()
+			},
+			want:     "UPDATE `jobs` SET `status` = ?",
+			wantArgs: []interface{}{"done"},
+			wantErr:  ErrListIsNotEmpty,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := tt.build(New().SetDialector(tt.d))
+			errs := append([]error{}, b.ErrList...)
+			q, err := b.Build()
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("error: %s", err)
+			}
+			if tt.wantErr != nil && (len(errs) == 0 || (tt.wantErr != ErrListIsNotEmpty && !errors.Is(errs[0], tt.wantErr))) {
+				t.Errorf("ErrList = %v, want %v", errs, tt.wantErr)
+			}
+			if q.Query != tt.want {
+				t.Errorf("\ngot:\n%s\nwant:\n%s\n", q.Query, tt.want)
+			}
+			if !reflect.DeepEqual(tt.wantArgs, q.Args) {
+				t.Errorf("\ngotArgs:\n%#v\nwantArgs:\n%#v\n", q.Args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestInsertSources(t *testing.T) {
+	tests := []struct {
+		name     string
+		d        Dialector
+		build    func(b *Builder) *Builder
+		want     string
+		wantArgs []interface{}
+		wantErr  error
+	}{
+		{
+			name: "from_select",
+			d:    mysqlDialector,
+			build: func(b *Builder) *Builder {
+				old := New().Select("id", "name").From("users").Where(Lt("last_login", "2020-01-01"))
+				return b.Insert("archive", "id", "name").FromSelect(old)
+			},
+			want:     "INSERT INTO `archive` (`id`, `name`) SELECT `id`, `name` FROM `users` WHERE `last_login` < ?",
+			wantArgs: []interface{}{"2020-01-01"},
+		},
+		{
+			name: "from_select_upsert_returning",
+			d:    postgresDialector,
+			build: func(b *Builder) *Builder {
+				q, _ := New().SetDialector(postgresDialector).Select("id", "name").From("users").Where(Eq("status", 0), And("id", ">", 10)).Build()
+				return b.Insert("archive", "id", "name").Values([]interface{}{1, "coder"}).FromSelect(q).
+					OnConflict("id").DoNothing().Returning("id")
+			},
+			want:     `INSERT INTO "archive" ("id", "name") SELECT "id", "name" FROM "users" WHERE "status" = $1 AND "id" > $2 ON CONFLICT ("id") DO NOTHING RETURNING "id"`,
+			wantArgs: []interface{}{0, 10},
+		},
+		{
+			name: "values_after_from_select",
+			d:    mysqlDialector,
+			build: func(b *Builder) *Builder {
+				return b.Insert("archive", "id").FromSelect(New().Select("id").From("users")).Values([]interface{}{1}, []interface{}{2})
+			},
+			want:     "INSERT INTO `archive` (`id`) VALUES (?), (?)",
+			wantArgs: []interface{}{1, 2},
+		},
+		{
+			name: "default_values_mysql",
+			d:    mysqlDialector,
+			build: func(b *Builder) *Builder {
+				return b.Insert("events", "id").Values([]interface{}{1}).DefaultValues()
+			},
+			want:     "INSERT INTO `events` () VALUES ()",
+			wantArgs: []interface{}{},
+		},
+		{
+			name: "default_values_postgres",
+			d:    postgresDialector,
+			build: func(b *Builder) *Builder {
+				return b.Insert("events").DefaultValues().Returning("id")
+			},
+			want:     `INSERT INTO "events" DEFAULT VALUES RETURNING "id"`,
+			wantArgs: []interface{}{},
+		},
+		{
+			name: "default_marker",
+			d:    postgresDialector,
+			build: func(b *Builder) *Builder {
+				return b.Insert("users", "name", "role", "created_at", "a", "b", "c").
+					Values([]interface{}{"coder", Default, Default, 1, 2, 3}, []interface{}{"hacker", "admin", Default, 4, 5, 6})
+			},
+			want:     `INSERT INTO "users" ("name", "role", "created_at", "a", "b", "c") VALUES ($1, DEFAULT, DEFAULT, $2, $3, $4), ($5, $6, DEFAULT, $7, $8, $9)`,
+			wantArgs: []interface{}{"coder", 1, 2, 3, "hacker", "admin", 4, 5, 6},
+		},
+		{
+			name: "default_set",
+			d:    mysqlDialector,
+			build: func(b *Builder) *Builder {
+				return b.Update("users", NewFV("role", Default), NewFV("name", "coder")).Where(Eq("id", 1))
+			},
+			want:     "UPDATE `users` SET `role` = DEFAULT, `name` = ? WHERE `id` = ?",
+			wantArgs: []interface{}{"coder", 1},
+		},
+		{
+			name: "default_marker_sqlite",
+			d:    sqliteDialector,
+			build: func(b *Builder) *Builder {
+				return b.Insert("users", "name", "role").Values([]interface{}{"coder", Default})
+			},
+			want:     `INSERT INTO "users" ("name", "role") VALUES (?, {error: sqlite: DEFAULT: not supported by the sql dialect})`,
+			wantArgs: []interface{}{"coder"},
+			wantErr:  ErrNotSupported,
+		},
+		{
+			name: "empty_select",
+			d:    mysqlDialector,
+			build: func(b *Builder) *Builder {
+				return b.Insert("archive", "id").FromSelect(nil)
+			},
+			want:     "INSERT INTO `archive` (`id`)",
+			wantArgs: []interface{}{},
+			wantErr:  ErrEmptySubquery,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := tt.build(New().SetDialector(tt.d))
+			errs := append([]error{}, b.ErrList...)
+			q, err := b.Build()
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("error: %s", err)
+			}
+			if tt.wantErr != nil && (len(errs) != 1 || !errors.Is(errs[0], tt.wantErr)) {
+				t.Errorf("ErrList = %v, want %v", errs, tt.wantErr)
+			}
+			if q.Query != tt.want {
+				t.Errorf("\ngot:\n%s\nwant:\n%s\n", q.Query, tt.want)
+			}
+			if !reflect.DeepEqual(tt.wantArgs, q.Args) {
+				t.Errorf("\ngotArgs:\n%#v\nwantArgs:\n%#v\n", q.Args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+// smallDialector is a SQLite dialect accepting at most 7 parameters per statement.
+type smallDialector struct {
+	SQLiteDialector
+}
+
+func (smallDialector) MaxParameters() int {
+	return 7
+}
+
+func TestBuildBatch(t *testing.T) {
+	rows := make([][]interface{}, 700)
+	for i := range rows {
+		rows[i] = []interface{}{i, "name" + strconv.Itoa(i), i % 2}
+	}
+	b := New().SetDialector(sqliteDialector)
+	queries, err := b.Insert("users", "id", "name", "active").BuildBatch(rows...)
+	if err != nil {
+		t.Fatalf("error: %s", err)
+	}
+	if len(queries) != 3 {
+		t.Fatalf("got %d queries, want 3", len(queries))
+	}
+	for i, n := range []int{333, 333, 34} {
+		if len(queries[i].Args) != n*3 {
+			t.Errorf("query %d has %d args, want %d", i, len(queries[i].Args), n*3)
+		}
+	}
+	if !reflect.DeepEqual(queries[2].Args[:3], []interface{}{666, "name666", 0}) {
+		t.Errorf("query 2 starts with args %#v", queries[2].Args[:3])
+	}
+	if got := b.LastQueries(); len(got) != 3 || got[2] != queries[2] {
+		t.Errorf("LastQueries() = %#v", got)
+	}
+	if b.HasClause(IntoClause) {
+		t.Error("BuildBatch() did not reset the builder")
+	}
+
+	// The parameters of the other clauses count against the limit, and the
+	// clauses are repeated in every query.
+	queries, err = New().SetDialector(smallDialector{}).Insert("counters", "name", "hits").
+		Values([]interface{}{"ignored", 0}).
+		OnConflict("name").DoUpdate(NewFV("hits", NewExpr("hits + ?", 1))).
+		BuildBatch([]interface{}{"a", 1}, []interface{}{"b", 2}, []interface{}{"c", 3}, []interface{}{"d", Default}, []interface{}{"e", 5})
+	if !errors.Is(err, ErrListIsNotEmpty) {
+		t.Errorf("error = %v, want %v", err, ErrListIsNotEmpty)
+	}
+	queries, err = New().SetDialector(smallDialector{}).Insert("counters", "name", "hits").
+		Values([]interface{}{"ignored", 0}).
+		OnConflict("name").DoUpdate(NewFV("hits", NewExpr("hits + ?", 1))).Returning("id").
+		BuildBatch([]interface{}{"a", 1}, []interface{}{"b", 2}, []interface{}{"c", 3}, []interface{}{"d", 4}, []interface{}{"e", 5})
+	if err != nil {
+		t.Fatalf("error: %s", err)
+	}
+	want := []*Query{
+		NewQuery(`INSERT INTO "counters" ("name", "hits") VALUES (?, ?), (?, ?), (?, ?) ON CONFLICT ("name") DO UPDATE SET "hits" = hits + ? RETURNING "id"`, "a", 1, "b", 2, "c", 3, 1),
+		NewQuery(`INSERT INTO "counters" ("name", "hits") VALUES (?, ?), (?, ?) ON CONFLICT ("name") DO UPDATE SET "hits" = hits + ? RETURNING "id"`, "d", 4, "e", 5, 1),
+	}
+	if !reflect.DeepEqual(queries, want) {
+		t.Errorf("\ngot:\n%#v\nwant:\n%#v\n", queries, want)
+	}
+
+	errTests := []struct {
+		name    string
+		build   func() ([]*Query, error)
+		wantErr error
+	}{
+		{
+			name: "arity_into",
+			build: func() ([]*Query, error) {
+				return New().Insert("users", "id", "name").BuildBatch([]interface{}{1, "a"}, []interface{}{2})
+			},
+			wantErr: ErrValuesMismatch,
+		},
+		{
+			name: "arity_first_row",
+			build: func() ([]*Query, error) {
+				return New().Insert("users").BuildBatch([]interface{}{1, "a"}, []interface{}{2, "b", true})
+			},
+			wantErr: ErrValuesMismatch,
+		},
+		{
+			name: "empty_row",
+			build: func() ([]*Query, error) {
+				return New().Insert("users").BuildBatch([]interface{}{})
+			},
+			wantErr: ErrValuesMismatch,
+		},
+		{
+			name: "too_many_columns",
+			build: func() ([]*Query, error) {
+				return New().SetDialector(smallDialector{}).Insert("t", "a", "b", "c", "d", "e", "f", "g", "h").
+					BuildBatch([]interface{}{1, 2, 3, 4, 5, 6, 7, 8})
+			},
+		},
+		{
+			name: "not_insert",
+			build: func() ([]*Query, error) {
+				return New().Update("users", NewFV("a", 1)).BuildBatch([]interface{}{1})
+			},
+		},
+	}
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			queries, err := tt.build()
+			if err == nil || queries != nil {
+				t.Errorf("BuildBatch() = %v, %v, want an error", queries, err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	var verr *ValuesError
+	_, err = New().Insert("users", "id", "name").BuildBatch([]interface{}{1, "a"}, []interface{}{2, "b"}, []interface{}{3})
+	if !errors.As(err, &verr) || *verr != (ValuesError{Row: 2, Got: 1, Want: 2}) {
+		t.Errorf("BuildBatch() error = %#v, want row 2", err)
+	}
+
+	if queries, err = New().Insert("users", "id").BuildBatch(); queries != nil || err != nil {
+		t.Errorf("BuildBatch() = %v, %v, want no queries", queries, err)
+	}
+}
+
+func TestValuesArity(t *testing.T) {
+	tests := []struct {
+		name     string
+		build    func(b *Builder) *Builder
+		want     string
+		wantArgs []interface{}
+		wantErrs []*ValuesError
+	}{
+		{
+			name: "matching",
+			build: func(b *Builder) *Builder {
+				return b.Insert("users", "name", "age").Values([]interface{}{"coder", 25}, []interface{}{"hacker", 30})
+			},
+			want:     "INSERT INTO `users` (`name`, `age`) VALUES (?, ?), (?, ?)",
+			wantArgs: []interface{}{"coder", 25, "hacker", 30},
+		},
+		{
+			name: "mismatched_into",
+			build: func(b *Builder) *Builder {
+				return b.Insert("users", "name", "age").
+					Values([]interface{}{"coder", 25}, []interface{}{"hacker"}).
+					Values([]interface{}{"admin", 40, true})
+			},
+			want:     "INSERT INTO `users` (`name`, `age`) VALUES (?, ?)",
+			wantArgs: []interface{}{"coder", 25},
+			wantErrs: []*ValuesError{{Row: 1, Got: 1, Want: 2}, {Row: 2, Got: 3, Want: 2}},
+		},
+		{
+			name: "empty_row",
+			build: func(b *Builder) *Builder {
+				return b.Insert("users", "name").Values([]interface{}{}, []interface{}{"coder"}, nil)
+			},
+			want:     "INSERT INTO `users` (`name`) VALUES (?)",
+			wantArgs: []interface{}{"coder"},
+			wantErrs: []*ValuesError{{Row: 0, Got: 0, Want: 1}, {Row: 2, Got: 0, Want: 1}},
+		},
+		{
+			name: "first_row_without_into",
+			build: func(b *Builder) *Builder {
+				return b.Insert("users").Values([]interface{}{1, "coder"}, []interface{}{2, "hacker", 30}, []interface{}{3, "admin"})
+			},
+			want:     "INSERT INTO `users` VALUES (?, ?), (?, ?)",
+			wantArgs: []interface{}{1, "coder", 3, "admin"},
+			wantErrs: []*ValuesError{{Row: 1, Got: 3, Want: 2}},
+		},
+		{
+			name: "reset_by_from_select",
+			build: func(b *Builder) *Builder {
+				return b.Insert("users").Values([]interface{}{1, "coder"}).
+					FromSelect(New().Select("id").From("admins")).
+					Values([]interface{}{2})
+			},
+			want:     "INSERT INTO `users` VALUES (?)",
+			wantArgs: []interface{}{2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := tt.build(New())
+			var errs []*ValuesError
+			for _, err := range b.ErrList {
+				var verr *ValuesError
+				if !errors.As(err, &verr) || !errors.Is(err, ErrValuesMismatch) {
+					t.Errorf("unexpected error: %v", err)
+					continue
+				}
+				errs = append(errs, verr)
+			}
+			if !reflect.DeepEqual(errs, tt.wantErrs) {
+				t.Errorf("\ngotErrs:\n%v\nwantErrs:\n%v\n", errs, tt.wantErrs)
+			}
+			q, err := b.Build()
+			if len(tt.wantErrs) == 0 && err != nil {
+				t.Errorf("error: %s", err)
+			}
+			if len(tt.wantErrs) > 0 && err != ErrListIsNotEmpty {
+				t.Errorf("error = %v, want %v", err, ErrListIsNotEmpty)
+			}
+			if q.Query != tt.want {
+				t.Errorf("\ngot:\n%s\nwant:\n%s\n", q.Query, tt.want)
+			}
+			if !reflect.DeepEqual(tt.wantArgs, q.Args) {
+				t.Errorf("\ngotArgs:\n%#v\nwantArgs:\n%#v\n", q.Args, tt.wantArgs)
+			}
+		})
+	}
+
+	err := &ValuesError{Row: 3, Got: 1, Want: 2}
+	if want := "values do not match columns: row 3 has 1 values, want 2"; err.Error() != want {
+		t.Errorf("ValuesError.Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestIdentifierPattern(t *testing.T) {
+	want := "SELECT `u`.`id`, `u`.`a``b` FROM `db`.`users` WHERE `u`.`a``b` = ?"
+	q, err := New().Select("u.id", "u.a`b").From("db.users").Where(Eq("u.a`b", 1)).Build()
+	if err != nil {
+		t.Errorf("error: %s", err)
+	}
+	if q.Query != want {
+		t.Errorf("\ngot:\n%s\nwant:\n%s\n", q.Query, want)
+	}
+
+	b := New().SetIdentifierPattern(DefaultIdentifierPattern)
+	want = "SELECT `users`.* FROM `db`.`users` WHERE `status` = ? ORDER BY `created_at` DESC"
+	q, err = b.Select("users.*").From("db.users").Where(Eq("status", 1)).OrderBy(Desc("created_at")).Build()
+	if err != nil {
+		t.Errorf("error: %s", err)
+	}
+	if q.Query != want {
+		t.Errorf("\ngot:\n%s\nwant:\n%s\n", q.Query, want)
+	}
+
+	b.Select("*").From("users").OrderBy(Desc("id`; DROP TABLE users; --"))
+	errs := append([]error{}, b.Clone().ErrList...)
+	if _, err = b.Build(); err != ErrListIsNotEmpty {
+		t.Errorf("error = %v, want %v", err, ErrListIsNotEmpty)
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], ErrInvalidIdentifier) {
+		t.Errorf("ErrList = %v, want %v", errs, ErrInvalidIdentifier)
+	}
+
+	// function-looking fields are escaped and validated, aggregate calls in HAVING are validated
+	tests := []struct {
+		name  string
+		build func(b *Builder) *Builder
+		want  string
+	}{
+		{
+			name:  "order_by",
+			build: func(b *Builder) *Builder { return b.OrderBy(Asc("id); DROP TABLE t; --(x)")) },
+			want:  "SELECT * FROM `t` ORDER BY `id); DROP TABLE t; --(x)` ASC",
+		},
+		{
+			name:  "where",
+			build: func(b *Builder) *Builder { return b.Where(Eq("LOWER(name)", "a")) },
+			want:  "SELECT * FROM `t` WHERE `LOWER(name)` = ?",
+		},
+		{
+			name:  "group_by",
+			build: func(b *Builder) *Builder { return b.GroupBy("DATE(x); --(y)") },
+			want:  "SELECT * FROM `t` GROUP BY `DATE(x); --(y)`",
+		},
+		{
+			name:  "having",
+			build: func(b *Builder) *Builder { return b.Having(Gt("id); DROP TABLE t; --(x)", 1)) },
+			want:  "SELECT * FROM `t` HAVING `id); DROP TABLE t; --(x)` > ?",
+		},
+		{
+			name:  "having_aggregate",
+			build: func(b *Builder) *Builder { return b.Having(Gt("SUM(1a)", 1)) },
+			want:  "SELECT * FROM `t` HAVING SUM(1a) > ?",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := tt.build(New().SetIdentifierPattern(DefaultIdentifierPattern).Select("*").From("t"))
+			errs := append([]error{}, b.ErrList...)
+			q, _ := b.Build()
+			if q.Query != tt.want {
+				t.Errorf("\ngot:\n%s\nwant:\n%s\n", q.Query, tt.want)
+			}
+			if len(errs) != 1 || !errors.Is(errs[0], ErrInvalidIdentifier) {
+				t.Errorf("ErrList = %v, want %v", errs, ErrInvalidIdentifier)
+			}
+		})
+	}
+	want = "SELECT `user_id` FROM `orders` GROUP BY `user_id` HAVING COUNT(*) > ? AND COUNT(1) > ? AND SUM(DISTINCT o.amount) > ? AND count(distinct user_id) > ?"
+	q, err = b.Select("user_id").From("orders").GroupBy("user_id").
+		Having(Gt("COUNT(*)", 1), And("COUNT(1)", ">", 1), And("SUM(DISTINCT o.amount)", ">", 1), And("count(distinct user_id)", ">", 1)).
+		Build()
+	if err != nil {
+		t.Errorf("error: %s", err)
+	}
+	if q.Query != want {
+		t.Errorf("\ngot:\n%s\nwant:\n%s\n", q.Query, want)
+	}
+
+	if _, err = b.SetIdentifierPattern(nil).Select("*").From("first name").Build(); err != nil {
+		t.Errorf("error: %s", err)
+	}
+}
+
+func Test_aliasIndex(t *testing.T) {
+	tests := []struct {
+		column string
+		want   int
+	}{
+		{column: "name", want: -1},
+		{column: "u.name AS author", want: 6},
+		{column: "u.name as author", want: 6},
+		{column: "CAST(x AS INT)", want: -1},
+		{column: "CAST(x AS INT) AS y", want: 14},
+		{column: "a AS b AS c", want: 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.column, func(t *testing.T) {
+			if got := aliasIndex(tt.column); got != tt.want {
+				t.Errorf("aliasIndex() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
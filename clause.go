@@ -0,0 +1,164 @@
+// Package builder provides a fluent SQL query builder with support for multiple SQL dialects.
+package builder
+
+import "strings"
+
+// Clause identifies a clause of the statement held by a Builder.
+// Clauses are stored separately and only joined in SQL order when the query
+// is built, so they can be set in any order, replaced or removed.
+//
+// Example:
+//
+//	b.Select("*").From("users").Limit(10).Where(builder.Eq("status", "active"))
+//	b.RemoveClause(builder.LimitClause)
+//	// Generates: SELECT * FROM `users` WHERE `status` = ?
+type Clause int
+
+// Clauses of a statement, in the order they are rendered.
+const (
+	// prefixClause holds the text added by AppendPre.
+	prefixClause Clause = iota
+	// WithClause holds the common table expressions of a statement, extended by With and WithRecursive.
+	WithClause
+
+	// headClause holds the statement itself: "SELECT fields", "INSERT INTO table",
+	// "UPDATE table", "DELETE FROM table" or a raw query.
+	headClause
+
+	// IntoClause is the column list of an INSERT or REPLACE query, set by Into.
+	IntoClause
+
+	// ValuesClause is the VALUES list of an INSERT or REPLACE query, extended by Values,
+	// or the source of its rows set by FromSelect or DefaultValues.
+	ValuesClause
+
+	// SetClause is the SET list of an UPDATE query, extended by Set.
+	SetClause
+
+	// FromClause is the FROM clause of a SELECT query, set by From, FromSub or FromRaw.
+	FromClause
+
+	// JoinClause holds all the joins of a query, extended by Join, LeftJoin, etc.
+	JoinClause
+
+	// WhereClause is the WHERE clause, set by Where or WhereRaw and extended by And, Or, In, etc.
+	WhereClause
+
+	// GroupByClause is the GROUP BY clause, set by GroupBy.
+	GroupByClause
+
+	// HavingClause is the HAVING clause, set by Having.
+	HavingClause
+
+	// WindowClause holds the named windows of a SELECT query, extended by Window.
+	WindowClause
+
+	// CompoundClause holds the set operations combining a SELECT query with other
+	// queries, extended by Union, UnionAll, Intersect and Except.
+	CompoundClause
+
+	// OrderByClause is the ORDER BY clause, set by OrderBy.
+	OrderByClause
+
+	// LimitClause is the pagination clause, set by Limit, Offset and Page.
+	LimitClause
+
+	// LockClause is the row locking clause of a SELECT query, set by ForUpdate and ForShare.
+	LockClause
+
+	// UpsertClause is the conflict resolution clause of an INSERT query, set by DoUpdate and DoNothing.
+	UpsertClause
+
+	// ReturningClause is the RETURNING clause, set by Returning.
+	ReturningClause
+
+	// clauseCount is the number of clauses of a statement.
+	clauseCount
+)
+
+// clause is a part of a statement, written with "?" placeholders along with its arguments.
+type clause struct {
+	// keyword is written before the content, e.g. " WHERE "
+	keyword string
+	// sql is the content of the clause
+	sql string
+	// args holds the arguments of the placeholders in sql
+	args []interface{}
+}
+
+// clause returns the given clause of the statement, creating it if needed,
+// and makes it the current clause, to which Append adds.
+func (b *Builder) clause(c Clause) *clause {
+	if b.clauses[c] == nil {
+		b.clauses[c] = &clause{}
+	}
+	b.current = c
+	return b.clauses[c]
+}
+
+// replaceClause replaces the given clause of the statement with an empty one
+// and makes it the current clause.
+func (b *Builder) replaceClause(c Clause, keyword string) *clause {
+	b.clauses[c] = &clause{keyword: keyword}
+	b.current = c
+	return b.clauses[c]
+}
+
+// HasClause reports whether the given clause of the statement has been set.
+func (b *Builder) HasClause(c Clause) bool {
+	return c >= 0 && c < clauseCount && b.clauses[c] != nil
+}
+
+// RemoveClause removes the given clauses from the statement, along with their arguments.
+// Errors already collected in ErrList are kept.
+// It returns the Builder instance for method chaining.
+//
+// Example:
+//
+//	count := b.Select("*").From("users").Where(builder.Eq("status", "active")).OrderBy(builder.Desc("id")).Limit(10)
+//	count.RemoveClause(builder.OrderByClause, builder.LimitClause)
+func (b *Builder) RemoveClause(clauses ...Clause) *Builder {
+	for _, c := range clauses {
+		if c < 0 || c >= clauseCount {
+			continue
+		}
+		b.clauses[c] = nil
+		switch c {
+		case LimitClause:
+			b.pagination = nil
+		case LockClause:
+			b.locking = nil
+		case IntoClause:
+			b.intoFields = b.intoFields[:0]
+		case ValuesClause:
+			b.valuesRows, b.valuesArity = 0, 0
+		case UpsertClause:
+			b.upsert = nil
+		}
+	}
+	return b
+}
+
+// render joins the clauses of the statement in SQL order and returns
+// the query string with "?" placeholders along with its arguments.
+// An upsert clause not rendered yet is rendered on a copy of the builder.
+func (b *Builder) render() (string, []interface{}) {
+	if b.upsert != nil && b.clauses[UpsertClause] != nil {
+		c := b.Clone()
+		c.buildUpsert()
+		return c.render()
+	}
+	var (
+		sb   strings.Builder
+		args = []interface{}{}
+	)
+	for _, c := range b.clauses {
+		if c == nil {
+			continue
+		}
+		sb.WriteString(c.keyword)
+		sb.WriteString(c.sql)
+		args = append(args, c.args...)
+	}
+	return sb.String(), args
+}
@@ -13,6 +13,64 @@ package builder
 //	b.Select("*").From("users").Where(builder.In("status", values...))
 type Values []interface{}
 
+// Column represents a reference to a database column used as a condition value.
+// Unlike other values it is escaped as an identifier instead of being bound
+// as a parameter, which allows comparing two columns (e.g. in JOIN ... ON).
+//
+// Example usage:
+//
+//	// Creates: `users`.`id` = `orders`.`user_id`
+//	builder.Eq("users.id", builder.Column("orders.user_id"))
+type Column string
+
+// Expr represents a raw SQL expression along with the arguments of its "?" placeholders.
+// It is rendered as is, without escaping, wherever a value is expected: in conditions,
+// in SET lists (through FieldValue.Value), in SelectExpr and in OrderBy (through AscExpr
+// and DescExpr). An Alias is rendered as "expr AS alias" in SELECT lists only.
+// A Window makes the expression a window function call, rendered as "expr OVER (window)".
+//
+// Warning: Do not build the SQL of an expression from user-provided input,
+// pass such values as arguments instead.
+//
+// Example usage:
+//
+//	// Creates: SET `views` = views + ?
+//	b.Update("posts", builder.NewFV("views", builder.NewExpr("views + ?", 1)))
+//
+//	// Creates: SELECT price * qty AS `total`
+//	b.SelectExpr(builder.NewExpr("price * qty").As("total"))
+type Expr struct {
+	SQL    string        // The raw SQL of the expression, with "?" placeholders
+	Args   []interface{} // The arguments of the placeholders
+	Alias  string        // The column alias of the expression in a SELECT list
+	Window *Window       // The window of a window function call, see Over
+}
+
+// NewExpr creates a new Expr with the given raw SQL and arguments.
+func NewExpr(sql string, args ...interface{}) Expr {
+	return Expr{SQL: sql, Args: args}
+}
+
+// As returns a copy of the expression with the given column alias,
+// rendered as "expr AS alias" in SELECT lists.
+func (e Expr) As(alias string) Expr {
+	e.Alias = alias
+	return e
+}
+
+// Over returns a copy of the expression computed over the given window,
+// rendered as "expr OVER (window)", or "expr OVER name" for a window only
+// naming a window defined by Builder.Window.
+//
+// Example usage:
+//
+//	// Creates: ROW_NUMBER() OVER (PARTITION BY `dept` ORDER BY `salary` DESC) AS `rank`
+//	builder.NewExpr("ROW_NUMBER()").Over(builder.NewWindow().PartitionBy("dept").OrderBy(builder.Desc("salary"))).As("rank")
+func (e Expr) Over(w *Window) Expr {
+	e.Window = w
+	return e
+}
+
 // Condition represents a SQL condition that can be used in WHERE clauses or ORDER BY statements.
 // It supports various SQL operators and can be combined using AND/OR logic.
 //
@@ -34,6 +92,10 @@ type Condition struct {
 	AndOr    bool          // Logical operator: true for AND, false for OR
 	Operator string        // SQL operator (e.g., =, >, LIKE, IN, etc.)
 	Values   []interface{} // The values to compare against the field
+
+	// Conditions holds the nested conditions of a composite condition
+	// created by AllOf, AnyOf or Not, whose Operator is "AND", "OR" or "NOT".
+	Conditions []*Condition
 }
 
 // newCondition creates a new Condition with the specified parameters.
@@ -85,6 +147,22 @@ func Or(field string, op string, values ...interface{}) *Condition {
 	return newCondition(false, field, op, values)
 }
 
+// On creates a new column-to-column condition combined with AND logic,
+// typically used for JOIN ... ON clauses. Both sides are escaped as identifiers.
+//
+// Parameters:
+//   - field: The column on the left-hand side
+//   - op: The SQL operator (e.g., "=", ">", etc.)
+//   - column: The column on the right-hand side
+//
+// Example:
+//
+//	// Creates: ON `u`.`id` = `o`.`user_id`
+//	b.Join("orders o", builder.On("u.id", "=", "o.user_id"))
+func On(field string, op string, column string) *Condition {
+	return newCondition(true, field, op, []interface{}{Column(column)})
+}
+
 // Between creates a new BETWEEN condition for the specified field.
 // The values parameter should contain exactly two values defining the range.
 //
@@ -116,6 +194,7 @@ func NotBetween(field string, values ...interface{}) *Condition {
 }
 
 // Eq creates a new equality condition for the specified field and value.
+// A nil value renders "IS NULL" when the Builder is set with SetNilAsNull.
 //
 // Parameters:
 //   - field: The database column or field name
@@ -130,6 +209,7 @@ func Eq(field string, value interface{}) *Condition {
 }
 
 // NotEq creates a new inequality condition for the specified field and value.
+// A nil value renders "IS NOT NULL" when the Builder is set with SetNilAsNull.
 //
 // Parameters:
 //   - field: The database column or field name
@@ -143,6 +223,64 @@ func NotEq(field string, value interface{}) *Condition {
 	return newCondition(false, field, "!=", []interface{}{value})
 }
 
+// IsNull creates a new IS NULL condition for the specified field.
+//
+// Parameters:
+//   - field: The database column or field name
+//
+// Example:
+//
+//	// Creates: WHERE deleted_at IS NULL
+//	b.Where(builder.IsNull("deleted_at"))
+func IsNull(field string) *Condition {
+	return newCondition(false, field, "IS NULL", nil)
+}
+
+// IsNotNull creates a new IS NOT NULL condition for the specified field.
+//
+// Parameters:
+//   - field: The database column or field name
+//
+// Example:
+//
+//	// Creates: WHERE deleted_at IS NOT NULL
+//	b.Where(builder.IsNotNull("deleted_at"))
+func IsNotNull(field string) *Condition {
+	return newCondition(false, field, "IS NOT NULL", nil)
+}
+
+// IsDistinctFrom creates a new NULL-safe inequality condition for the specified field and value,
+// which is true when exactly one side is NULL. It is rendered by the current SQL dialect,
+// e.g. "IS DISTINCT FROM" for PostgreSQL and "NOT (... <=> ...)" for MySQL.
+//
+// Parameters:
+//   - field: The database column or field name
+//   - value: The value to compare against the field
+//
+// Example:
+//
+//	// Creates: WHERE manager_id IS DISTINCT FROM 1 (PostgreSQL)
+//	b.Where(builder.IsDistinctFrom("manager_id", 1))
+func IsDistinctFrom(field string, value interface{}) *Condition {
+	return newCondition(false, field, "IS DISTINCT FROM", []interface{}{value})
+}
+
+// IsNotDistinctFrom creates a new NULL-safe equality condition for the specified field and value,
+// which is also true when both sides are NULL. It is rendered by the current SQL dialect,
+// e.g. "IS NOT DISTINCT FROM" for PostgreSQL and "<=>" for MySQL.
+//
+// Parameters:
+//   - field: The database column or field name
+//   - value: The value to compare against the field
+//
+// Example:
+//
+//	// Creates: WHERE manager_id <=> ? (MySQL)
+//	b.Where(builder.IsNotDistinctFrom("manager_id", managerID))
+func IsNotDistinctFrom(field string, value interface{}) *Condition {
+	return newCondition(false, field, "IS NOT DISTINCT FROM", []interface{}{value})
+}
+
 // Gt creates a new greater than condition for the specified field and value.
 //
 // Parameters:
@@ -257,6 +395,73 @@ func NotIn(field string, values ...interface{}) *Condition {
 	return newCondition(false, field, "NOT IN", values)
 }
 
+// Exists creates a new EXISTS condition for the given sub-query,
+// which can be a built *Query or an un-built *Builder.
+//
+// Parameters:
+//   - sub: The sub-query to check for rows
+//
+// Example:
+//
+//	// Creates: WHERE EXISTS (SELECT 1 FROM orders WHERE ...)
+//	b.Where(builder.Exists(sub))
+func Exists(sub interface{}) *Condition {
+	return newCondition(false, "", "EXISTS", []interface{}{sub})
+}
+
+// NotExists creates a new NOT EXISTS condition for the given sub-query,
+// which can be a built *Query or an un-built *Builder.
+//
+// Parameters:
+//   - sub: The sub-query to check for rows
+//
+// Example:
+//
+//	// Creates: WHERE NOT EXISTS (SELECT 1 FROM orders WHERE ...)
+//	b.Where(builder.NotExists(sub))
+func NotExists(sub interface{}) *Condition {
+	return newCondition(false, "", "NOT EXISTS", []interface{}{sub})
+}
+
+// AllOf creates a composite condition that is true when all the given conditions are true.
+// The conditions are joined with AND in parentheses, whatever their own AndOr logic,
+// and may be composite conditions themselves, so they nest to any depth.
+// The composite condition is combined with AND logic when listed along with other conditions,
+// and can be used anywhere a *Condition is accepted (Where, And, Or, Having, Join, ...).
+//
+// Example:
+//
+//	// Creates: WHERE ((`a` = ? OR `b` = ?) AND (`c` = ? OR (`d` = ? AND `e` = ?)))
+//	b.Where(builder.AllOf(
+//		builder.AnyOf(builder.Eq("a", 1), builder.Eq("b", 2)),
+//		builder.AnyOf(builder.Eq("c", 3), builder.AllOf(builder.Eq("d", 4), builder.Eq("e", 5))),
+//	))
+func AllOf(conds ...*Condition) *Condition {
+	return &Condition{AndOr: true, Operator: "AND", Conditions: conds}
+}
+
+// AnyOf creates a composite condition that is true when any of the given conditions is true.
+// The conditions are joined with OR in parentheses. See AllOf for details.
+//
+// Example:
+//
+//	// Creates: WHERE `status` = ? AND (`role` = ? OR `age` > ?)
+//	b.Where(builder.Eq("status", "active"), builder.AnyOf(builder.Eq("role", "admin"), builder.Gt("age", 18)))
+func AnyOf(conds ...*Condition) *Condition {
+	return &Condition{AndOr: true, Operator: "OR", Conditions: conds}
+}
+
+// Not creates a composite condition that negates the given condition,
+// which may be a composite condition itself. See AllOf for details.
+//
+// Example:
+//
+//	// Creates: WHERE NOT (`a` = ? OR `b` = ?)
+//	b.Where(builder.Not(builder.AnyOf(builder.Eq("a", 1), builder.Eq("b", 2))))
+func Not(cond *Condition) *Condition {
+	return &Condition{AndOr: true, Operator: "NOT", Conditions: []*Condition{cond}}
+}
+
 // NewConditionGroup creates a group of conditions that can be used together.
 // It accepts multiple conditions and returns them as a slice.
 //
@@ -297,6 +502,21 @@ func OrderBy(conds ...*Condition) (by []*Condition) {
 	return
 }
 
+// DescExpr creates a new descending ORDER BY condition for the given expression.
+//
+// Example:
+//
+//	// Creates: ORDER BY FIELD(status, ?, ?) DESC
+//	b.OrderBy(builder.DescExpr(builder.NewExpr("FIELD(status, ?, ?)", "active", "pending")))
+func DescExpr(e Expr) *Condition {
+	return &Condition{Asc: false, Values: []interface{}{e}}
+}
+
+// AscExpr creates a new ascending ORDER BY condition for the given expression.
+func AscExpr(e Expr) *Condition {
+	return &Condition{Asc: true, Values: []interface{}{e}}
+}
+
 // Desc creates a new descending ORDER BY condition for the specified field.
 // It is used to sort results in descending order.
 func Desc(field string) *Condition {
@@ -754,3 +754,155 @@ func TestNotLike(t *testing.T) {
 		})
 	}
 }
+
+func TestOn(t *testing.T) {
+	type args struct {
+		field  string
+		op     string
+		column string
+	}
+	tests := []struct {
+		name string
+		args args
+		want *Condition
+	}{
+		{
+			name: "users.id=orders.user_id",
+			args: args{
+				field:  "users.id",
+				op:     "=",
+				column: "orders.user_id",
+			},
+			want: &Condition{
+				AndOr:    true,
+				Field:    "users.id",
+				Operator: "=",
+				Values:   []interface{}{Column("orders.user_id")},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if gotCond := On(tt.args.field, tt.args.op, tt.args.column); !reflect.DeepEqual(gotCond, tt.want) {
+				t.Errorf("On() = \n%#v\n, want\n%#v", gotCond, tt.want)
+			}
+		})
+	}
+}
+
+func TestExists(t *testing.T) {
+	sub := NewQuery("SELECT 1 FROM `orders` WHERE `user_id` = ?", 1)
+	tests := []struct {
+		name string
+		sub  interface{}
+		want *Condition
+	}{
+		{
+			name: "query",
+			sub:  sub,
+			want: &Condition{
+				Operator: "EXISTS",
+				Values:   []interface{}{sub},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if gotCond := Exists(tt.sub); !reflect.DeepEqual(gotCond, tt.want) {
+				t.Errorf("Exists() = \n%#v\n, want\n%#v", gotCond, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotExists(t *testing.T) {
+	sub := NewQuery("SELECT 1 FROM `orders` WHERE `user_id` = ?", 1)
+	tests := []struct {
+		name string
+		sub  interface{}
+		want *Condition
+	}{
+		{
+			name: "query",
+			sub:  sub,
+			want: &Condition{
+				Operator: "NOT EXISTS",
+				Values:   []interface{}{sub},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if gotCond := NotExists(tt.sub); !reflect.DeepEqual(gotCond, tt.want) {
+				t.Errorf("NotExists() = \n%#v\n, want\n%#v", gotCond, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsNull(t *testing.T) {
+	want := &Condition{Field: "deleted_at", Operator: "IS NULL"}
+	if gotCond := IsNull("deleted_at"); !reflect.DeepEqual(gotCond, want) {
+		t.Errorf("IsNull() = \n%#v\n, want\n%#v", gotCond, want)
+	}
+}
+
+func TestIsNotNull(t *testing.T) {
+	want := &Condition{Field: "deleted_at", Operator: "IS NOT NULL"}
+	if gotCond := IsNotNull("deleted_at"); !reflect.DeepEqual(gotCond, want) {
+		t.Errorf("IsNotNull() = \n%#v\n, want\n%#v", gotCond, want)
+	}
+}
+
+func TestIsDistinctFrom(t *testing.T) {
+	want := &Condition{Field: "manager_id", Operator: "IS DISTINCT FROM", Values: []interface{}{1}}
+	if gotCond := IsDistinctFrom("manager_id", 1); !reflect.DeepEqual(gotCond, want) {
+		t.Errorf("IsDistinctFrom() = \n%#v\n, want\n%#v", gotCond, want)
+	}
+	want = &Condition{Field: "manager_id", Operator: "IS NOT DISTINCT FROM", Values: []interface{}{nil}}
+	if gotCond := IsNotDistinctFrom("manager_id", nil); !reflect.DeepEqual(gotCond, want) {
+		t.Errorf("IsNotDistinctFrom() = \n%#v\n, want\n%#v", gotCond, want)
+	}
+}
+
+func TestAllOf(t *testing.T) {
+	want := &Condition{AndOr: true, Operator: "AND", Conditions: []*Condition{nameEqCoder, sexEqFemale}}
+	if gotCond := AllOf(nameEqCoder, sexEqFemale); !reflect.DeepEqual(gotCond, want) {
+		t.Errorf("AllOf() = \n%#v\n, want\n%#v", gotCond, want)
+	}
+}
+
+func TestAnyOf(t *testing.T) {
+	want := &Condition{AndOr: true, Operator: "OR", Conditions: []*Condition{nameEqCoder, sexEqFemale}}
+	if gotCond := AnyOf(nameEqCoder, sexEqFemale); !reflect.DeepEqual(gotCond, want) {
+		t.Errorf("AnyOf() = \n%#v\n, want\n%#v", gotCond, want)
+	}
+}
+
+func TestNot(t *testing.T) {
+	want := &Condition{AndOr: true, Operator: "NOT", Conditions: []*Condition{nameEqCoder}}
+	if gotCond := Not(nameEqCoder); !reflect.DeepEqual(gotCond, want) {
+		t.Errorf("Not() = \n%#v\n, want\n%#v", gotCond, want)
+	}
+}
+
+func TestNewExpr(t *testing.T) {
+	want := Expr{SQL: "views + ?", Args: []interface{}{1}}
+	if got := NewExpr("views + ?", 1); !reflect.DeepEqual(got, want) {
+		t.Errorf("NewExpr() = %#v, want %#v", got, want)
+	}
+	want = Expr{SQL: "COUNT(*)", Alias: "total"}
+	if got := NewExpr("COUNT(*)").As("total"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expr.As() = %#v, want %#v", got, want)
+	}
+}
+
+func TestAscDescExpr(t *testing.T) {
+	e := NewExpr("FIELD(status, ?)", "active")
+	if got, want := AscExpr(e), (&Condition{Asc: true, Values: []interface{}{e}}); !reflect.DeepEqual(got, want) {
+		t.Errorf("AscExpr() = %#v, want %#v", got, want)
+	}
+	if got, want := DescExpr(e), (&Condition{Values: []interface{}{e}}); !reflect.DeepEqual(got, want) {
+		t.Errorf("DescExpr() = %#v, want %#v", got, want)
+	}
+}
@@ -30,6 +30,7 @@ const (
 )
 
 // operMap defines the mapping between SQL operators and their expected number of values.
+// A value of 0 indicates an operator without values (e.g., IS NULL).
 // A value of 1 indicates a single-value operator (e.g., =, >).
 // A value of 2 indicates a two-value operator (e.g., BETWEEN).
 // A value of 3 indicates a multi-value operator (e.g., IN).
@@ -47,4 +48,11 @@ var operMap = map[string]int{
 	"NOT LIKE":    1, // Negative pattern matching
 	"BETWEEN":     2, // Range comparison
 	"NOT BETWEEN": 2, // Negative range comparison
+	"EXISTS":      1, // Sub-query returns any rows
+	"NOT EXISTS":  1, // Sub-query returns no rows
+
+	"IS NULL":              0, // Value is NULL
+	"IS NOT NULL":          0, // Value is not NULL
+	"IS DISTINCT FROM":     1, // NULL-safe not equal, rendered by the dialect
+	"IS NOT DISTINCT FROM": 1, // NULL-safe equal, rendered by the dialect
 }
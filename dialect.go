@@ -2,6 +2,7 @@
 package builder
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
 )
@@ -14,7 +15,9 @@ import (
 // of this interface to handle its specific SQL syntax requirements.
 type Dialector interface {
 	// Escape returns the escaped version of the provided identifiers
-	// according to the dialect's escaping rules.
+	// according to the dialect's escaping rules, joined with ", ".
+	// Qualified identifiers such as "schema.table.column" are escaped part by part,
+	// a "*" part is kept as is and embedded escape characters are doubled.
 	Escape(s ...string) string
 
 	// Placeholder returns the parameter placeholder for the given index.
@@ -24,6 +27,61 @@ type Dialector interface {
 	// GetEscapeChar returns the character used for escaping identifiers
 	// in the specific SQL dialect (e.g., backtick for MySQL, double quote for PostgreSQL).
 	GetEscapeChar() string
+
+	// LimitOffset returns the pagination clause for the given limit and offset,
+	// which are either numbers or placeholders. An empty string means the value is absent.
+	// For MySQL it returns "LIMIT offset, limit", for PostgreSQL "LIMIT limit OFFSET offset".
+	LimitOffset(limit, offset string) string
+
+	// Upsert returns the clause appended to an INSERT statement to resolve conflicts
+	// on the given escaped target columns, either with the given SET list or,
+	// when update is empty, by doing nothing.
+	Upsert(target []string, update string) (string, error)
+
+	// Excluded returns the reference to the value proposed for insertion
+	// of the given escaped column, for use in the update part of an upsert.
+	Excluded(column string) string
+
+	// DistinctFrom returns a NULL-safe comparison of the given escaped left and right
+	// operands, which is true when they are distinct if distinct is set, or when they
+	// are equal otherwise, treating NULL as a comparable value.
+	DistinctFrom(left, right string, distinct bool) string
+
+	// Returning returns the RETURNING clause for the given escaped columns,
+	// or an error if the dialect does not support it.
+	Returning(columns string) (string, error)
+
+	// SetOperation returns the keyword combining two queries with the given set
+	// operation ("UNION", "UNION ALL", "INTERSECT" or "EXCEPT"),
+	// or an error if the dialect does not support it.
+	SetOperation(operator string) (string, error)
+
+	// DefaultValue returns the keyword giving a column its default value in a VALUES row
+	// or a SET list, or an error if the dialect does not support it.
+	DefaultValue() (string, error)
+
+	// DefaultValues returns the clause following "INSERT INTO table" to insert
+	// a single row made of default values.
+	DefaultValues() string
+
+	// Lock returns the row locking clause of a SELECT query with the given strength
+	// ("UPDATE" or "SHARE"), locking the rows of the given escaped tables only if any,
+	// with the given wait policy ("", "NOWAIT" or "SKIP LOCKED"),
+	// or an error if the dialect does not support it.
+	Lock(strength string, tables []string, wait string) (string, error)
+
+	// MaxParameters returns the largest number of bound parameters of a statement,
+	// or 0 if there is no limit. Builder.BuildBatch splits rows to stay under it.
+	MaxParameters() int
+
+	// Savepoint returns the statement creating a savepoint with the given name.
+	Savepoint(name string) string
+
+	// ReleaseSavepoint returns the statement releasing the savepoint with the given name.
+	ReleaseSavepoint(name string) string
+
+	// RollbackToSavepoint returns the statement rolling back to the savepoint with the given name.
+	RollbackToSavepoint(name string) string
 }
 
 var (
@@ -57,13 +115,9 @@ type SQLiteDialector struct {
 }
 
 // Escape wraps MySQL identifiers with backticks and handles multiple identifiers
-// by joining them with "`, `".
+// by joining them with ", ", e.g. "`users`.`id`, `name`".
 func (MysqlDialector) Escape(s ...string) string {
-	if len(s) == 1 {
-		return "`" + s[0] + "`"
-	}
-	str := strings.Join(s, "`, `")
-	return "`" + strings.Trim(str, "`") + "`"
+	return quoteIdentifiers("`", s)
 }
 
 // GetEscapeChar returns the backtick character used for escaping MySQL identifiers.
@@ -76,14 +130,101 @@ func (MysqlDialector) Placeholder(index int) string {
 	return "?"
 }
 
+// LimitOffset returns "LIMIT offset, limit" for MySQL queries.
+// MySQL requires a LIMIT, so an offset alone is rendered with the largest possible limit.
+func (MysqlDialector) LimitOffset(limit, offset string) string {
+	switch {
+	case offset == "":
+		return "LIMIT " + limit
+	case limit == "":
+		return "LIMIT " + offset + ", 18446744073709551615"
+	}
+	return "LIMIT " + offset + ", " + limit
+}
+
+// Upsert returns an "ON DUPLICATE KEY UPDATE" clause for MySQL queries.
+// The target is ignored, except that DO NOTHING is emulated by setting
+// the first target column to itself.
+func (MysqlDialector) Upsert(target []string, update string) (string, error) {
+	if update == "" {
+		if len(target) <= 0 {
+			return "", ErrEmptyConflictTarget
+		}
+		update = target[0] + " = " + target[0]
+	}
+	return "ON DUPLICATE KEY UPDATE " + update, nil
+}
+
+// Excluded returns "VALUES(column)" for MySQL queries.
+func (MysqlDialector) Excluded(column string) string {
+	return "VALUES(" + column + ")"
+}
+
+// DistinctFrom returns "NOT (left <=> right)" or "left <=> right" for MySQL queries,
+// as MySQL has no IS DISTINCT FROM but a NULL-safe equal operator.
+func (MysqlDialector) DistinctFrom(left, right string, distinct bool) string {
+	if distinct {
+		return "NOT (" + left + " <=> " + right + ")"
+	}
+	return left + " <=> " + right
+}
+
+// Returning returns an error as MySQL does not support RETURNING clauses.
+func (MysqlDialector) Returning(columns string) (string, error) {
+	return "", fmt.Errorf("mysql: RETURNING: %w", ErrNotSupported)
+}
+
+// SetOperation returns UNION and UNION ALL for MySQL queries. INTERSECT and EXCEPT
+// are only available since MySQL 8.0.31, so they return an error.
+func (MysqlDialector) SetOperation(operator string) (string, error) {
+	switch operator {
+	case "UNION", "UNION ALL":
+		return operator, nil
+	}
+	return "", fmt.Errorf("mysql: %s: %w", operator, ErrNotSupported)
+}
+
+// DefaultValue returns "DEFAULT" for MySQL queries.
+func (MysqlDialector) DefaultValue() (string, error) {
+	return "DEFAULT", nil
+}
+
+// DefaultValues returns "() VALUES ()" for MySQL queries, as MySQL has no DEFAULT VALUES.
+func (MysqlDialector) DefaultValues() string {
+	return "() VALUES ()"
+}
+
+// Lock returns "FOR UPDATE" or "FOR SHARE", optionally followed by "OF tables" and
+// "NOWAIT" or "SKIP LOCKED", for MySQL queries (MySQL 8.0+). Tables given an alias
+// in the query must be referred to by their alias.
+func (MysqlDialector) Lock(strength string, tables []string, wait string) (string, error) {
+	return forLock("mysql", strength, tables, wait)
+}
+
+// MaxParameters returns 65535, the largest number of placeholders of a MySQL prepared statement.
+func (MysqlDialector) MaxParameters() int {
+	return 65535
+}
+
+// Savepoint returns "SAVEPOINT name" for MySQL.
+func (m MysqlDialector) Savepoint(name string) string {
+	return "SAVEPOINT " + m.Escape(name)
+}
+
+// ReleaseSavepoint returns "RELEASE SAVEPOINT name" for MySQL.
+func (m MysqlDialector) ReleaseSavepoint(name string) string {
+	return "RELEASE SAVEPOINT " + m.Escape(name)
+}
+
+// RollbackToSavepoint returns "ROLLBACK TO SAVEPOINT name" for MySQL.
+func (m MysqlDialector) RollbackToSavepoint(name string) string {
+	return "ROLLBACK TO SAVEPOINT " + m.Escape(name)
+}
+
 // Escape wraps PostgreSQL identifiers with double quotes and handles multiple identifiers
-// by joining them with '", "'.
+// by joining them with ", ", e.g. `"users"."id", "name"`.
 func (p PostgresqlDialector) Escape(s ...string) string {
-	if len(s) == 1 {
-		return `"` + s[0] + `"`
-	}
-	str := strings.Join(s, `", "`)
-	return `"` + strings.Trim(str, `"`) + `"`
+	return quoteIdentifiers(`"`, s)
 }
 
 // GetEscapeChar returns the double quote character used for escaping PostgreSQL identifiers.
@@ -97,14 +238,113 @@ func (p PostgresqlDialector) Placeholder(index int) string {
 	return "$" + strconv.Itoa(index)
 }
 
+// LimitOffset returns "LIMIT limit OFFSET offset" for PostgreSQL queries.
+// Either part is omitted when its value is absent.
+func (p PostgresqlDialector) LimitOffset(limit, offset string) string {
+	switch {
+	case offset == "":
+		return "LIMIT " + limit
+	case limit == "":
+		return "OFFSET " + offset
+	}
+	return "LIMIT " + limit + " OFFSET " + offset
+}
+
+// Upsert returns an "ON CONFLICT ... DO UPDATE SET" or "ON CONFLICT ... DO NOTHING"
+// clause for PostgreSQL queries. A target is required to update conflicting rows.
+func (p PostgresqlDialector) Upsert(target []string, update string) (string, error) {
+	return onConflict(target, update)
+}
+
+// Excluded returns "EXCLUDED.column" for PostgreSQL queries.
+func (p PostgresqlDialector) Excluded(column string) string {
+	return "EXCLUDED." + column
+}
+
+// DistinctFrom returns "left IS [NOT] DISTINCT FROM right" for PostgreSQL queries.
+func (p PostgresqlDialector) DistinctFrom(left, right string, distinct bool) string {
+	if distinct {
+		return left + " IS DISTINCT FROM " + right
+	}
+	return left + " IS NOT DISTINCT FROM " + right
+}
+
+// Returning returns "RETURNING columns" for PostgreSQL queries.
+func (p PostgresqlDialector) Returning(columns string) (string, error) {
+	return "RETURNING " + columns, nil
+}
+
+// SetOperation returns UNION, UNION ALL, INTERSECT and EXCEPT for PostgreSQL queries.
+func (p PostgresqlDialector) SetOperation(operator string) (string, error) {
+	return setOperation("postgres", operator)
+}
+
+// DefaultValue returns "DEFAULT" for PostgreSQL queries.
+func (p PostgresqlDialector) DefaultValue() (string, error) {
+	return "DEFAULT", nil
+}
+
+// DefaultValues returns "DEFAULT VALUES" for PostgreSQL queries.
+func (p PostgresqlDialector) DefaultValues() string {
+	return "DEFAULT VALUES"
+}
+
+// Lock returns "FOR UPDATE" or "FOR SHARE", optionally followed by "OF tables" and
+// "NOWAIT" or "SKIP LOCKED", for PostgreSQL queries. Tables given an alias
+// in the query must be referred to by their alias.
+func (p PostgresqlDialector) Lock(strength string, tables []string, wait string) (string, error) {
+	return forLock("postgres", strength, tables, wait)
+}
+
+// MaxParameters returns 65535, the largest number of parameters of a PostgreSQL statement.
+func (p PostgresqlDialector) MaxParameters() int {
+	return 65535
+}
+
+// Savepoint returns "SAVEPOINT name" for PostgreSQL.
+func (p PostgresqlDialector) Savepoint(name string) string {
+	return "SAVEPOINT " + p.Escape(name)
+}
+
+// ReleaseSavepoint returns "RELEASE SAVEPOINT name" for PostgreSQL.
+func (p PostgresqlDialector) ReleaseSavepoint(name string) string {
+	return "RELEASE SAVEPOINT " + p.Escape(name)
+}
+
+// RollbackToSavepoint returns "ROLLBACK TO SAVEPOINT name" for PostgreSQL.
+func (p PostgresqlDialector) RollbackToSavepoint(name string) string {
+	return "ROLLBACK TO SAVEPOINT " + p.Escape(name)
+}
+
 // Escape wraps SQLite identifiers with double quotes and handles multiple identifiers
-// by joining them with '", "'.
+// by joining them with ", ", e.g. `"users"."id", "name"`.
 func (s SQLiteDialector) Escape(strs ...string) string {
-	if len(strs) == 1 {
-		return `"` + strs[0] + `"`
+	return quoteIdentifiers(`"`, strs)
+}
+
+// quoteIdentifiers wraps each dot-separated part of the given identifiers with the quote
+// character, doubling the quote characters they contain, and joins them with ", ".
+// A "*" part, as in "users.*", is kept as is.
+func quoteIdentifiers(quote string, identifiers []string) string {
+	var sb strings.Builder
+	for i, identifier := range identifiers {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		for j, part := range strings.Split(identifier, ".") {
+			if j > 0 {
+				sb.WriteString(".")
+			}
+			if part == "*" {
+				sb.WriteString(part)
+				continue
+			}
+			sb.WriteString(quote)
+			sb.WriteString(strings.ReplaceAll(part, quote, quote+quote))
+			sb.WriteString(quote)
+		}
 	}
-	str := strings.Join(strs, `", "`)
-	return `"` + strings.Trim(str, `"`) + `"`
+	return sb.String()
 }
 
 // GetEscapeChar returns the double quote character used for escaping SQLite identifiers.
@@ -116,3 +356,284 @@ func (s SQLiteDialector) GetEscapeChar() string {
 func (s SQLiteDialector) Placeholder(index int) string {
 	return "?"
 }
+
+// LimitOffset returns "LIMIT limit OFFSET offset" for SQLite queries.
+// SQLite requires a LIMIT, so an offset alone is rendered with "LIMIT -1".
+func (s SQLiteDialector) LimitOffset(limit, offset string) string {
+	switch {
+	case offset == "":
+		return "LIMIT " + limit
+	case limit == "":
+		return "LIMIT -1 OFFSET " + offset
+	}
+	return "LIMIT " + limit + " OFFSET " + offset
+}
+
+// Upsert returns an "ON CONFLICT ... DO UPDATE SET" or "ON CONFLICT ... DO NOTHING"
+// clause for SQLite queries. A target is required to update conflicting rows.
+func (s SQLiteDialector) Upsert(target []string, update string) (string, error) {
+	return onConflict(target, update)
+}
+
+// Excluded returns "EXCLUDED.column" for SQLite queries.
+func (s SQLiteDialector) Excluded(column string) string {
+	return "EXCLUDED." + column
+}
+
+// DistinctFrom returns "left IS NOT right" or "left IS right" for SQLite queries,
+// which are NULL-safe in every SQLite version, unlike IS DISTINCT FROM (SQLite 3.39+).
+func (s SQLiteDialector) DistinctFrom(left, right string, distinct bool) string {
+	if distinct {
+		return left + " IS NOT " + right
+	}
+	return left + " IS " + right
+}
+
+// Returning returns "RETURNING columns" for SQLite queries (SQLite 3.35+).
+func (s SQLiteDialector) Returning(columns string) (string, error) {
+	return "RETURNING " + columns, nil
+}
+
+// SetOperation returns UNION, UNION ALL, INTERSECT and EXCEPT for SQLite queries.
+func (s SQLiteDialector) SetOperation(operator string) (string, error) {
+	return setOperation("sqlite", operator)
+}
+
+// DefaultValue returns an error as SQLite does not support DEFAULT in VALUES rows and SET lists.
+func (s SQLiteDialector) DefaultValue() (string, error) {
+	return "", fmt.Errorf("sqlite: DEFAULT: %w", ErrNotSupported)
+}
+
+// DefaultValues returns "DEFAULT VALUES" for SQLite queries.
+func (s SQLiteDialector) DefaultValues() string {
+	return "DEFAULT VALUES"
+}
+
+// Lock returns an error as SQLite locks whole databases and has no row locking clauses.
+func (s SQLiteDialector) Lock(strength string, tables []string, wait string) (string, error) {
+	return "", fmt.Errorf("sqlite: FOR %s: %w", strength, ErrNotSupported)
+}
+
+// MaxParameters returns 999, the default limit of host parameters before SQLite 3.32.0,
+// which raised it to 32766. Embed SQLiteDialector in a type overriding MaxParameters
+// to use a higher limit.
+func (s SQLiteDialector) MaxParameters() int {
+	return 999
+}
+
+// Savepoint returns "SAVEPOINT name" for SQLite.
+func (s SQLiteDialector) Savepoint(name string) string {
+	return "SAVEPOINT " + s.Escape(name)
+}
+
+// ReleaseSavepoint returns "RELEASE SAVEPOINT name" for SQLite.
+func (s SQLiteDialector) ReleaseSavepoint(name string) string {
+	return "RELEASE SAVEPOINT " + s.Escape(name)
+}
+
+// RollbackToSavepoint returns "ROLLBACK TO SAVEPOINT name" for SQLite.
+func (s SQLiteDialector) RollbackToSavepoint(name string) string {
+	return "ROLLBACK TO SAVEPOINT " + s.Escape(name)
+}
+
+// setOperation checks the standard set operations shared by PostgreSQL and SQLite.
+func setOperation(dialect, operator string) (string, error) {
+	switch operator {
+	case "UNION", "UNION ALL", "INTERSECT", "EXCEPT":
+		return operator, nil
+	}
+	return "", fmt.Errorf("%s: %s: %w", dialect, operator, ErrNotSupported)
+}
+
+// forLock renders the row locking clause shared by MySQL and PostgreSQL.
+func forLock(dialect, strength string, tables []string, wait string) (string, error) {
+	switch strength {
+	case "UPDATE", "SHARE":
+	default:
+		return "", fmt.Errorf("%s: FOR %s: %w", dialect, strength, ErrNotSupported)
+	}
+	clause := "FOR " + strength
+	if len(tables) > 0 {
+		clause += " OF " + strings.Join(tables, ", ")
+	}
+	switch wait {
+	case "":
+	case "NOWAIT", "SKIP LOCKED":
+		clause += " " + wait
+	default:
+		return "", fmt.Errorf("%s: %s: %w", dialect, wait, ErrNotSupported)
+	}
+	return clause, nil
+}
+
+// onConflict renders the standard ON CONFLICT clause shared by PostgreSQL and SQLite.
+func onConflict(target []string, update string) (string, error) {
+	var sb strings.Builder
+	sb.WriteString("ON CONFLICT")
+	if len(target) > 0 {
+		sb.WriteString(" (")
+		sb.WriteString(strings.Join(target, ", "))
+		sb.WriteString(")")
+	}
+	if update == "" {
+		sb.WriteString(" DO NOTHING")
+		return sb.String(), nil
+	}
+	if len(target) <= 0 {
+		return "", ErrEmptyConflictTarget
+	}
+	sb.WriteString(" DO UPDATE SET ")
+	sb.WriteString(update)
+	return sb.String(), nil
+}
+
+// rebind rewrites the "?" placeholders of a query into the placeholder style of
+// the given dialect, numbering them from 1 in the order they appear.
+// Question marks inside quoted strings, quoted identifiers or comments are left
+// untouched, and "??" can be used in raw fragments to emit a literal question mark
+// (e.g. for PostgreSQL JSON operators) whatever the dialect.
+//
+// Queries of dialects whose placeholder is "?" are only rewritten when they contain "??".
+func rebind(d Dialector, query string) string {
+	if strings.IndexByte(query, '?') < 0 {
+		return query
+	}
+	placeholder := func(int) string { return "?" }
+	if d != nil {
+		placeholder = d.Placeholder
+	}
+	if placeholder(1) == "?" && !strings.Contains(query, "??") {
+		return query
+	}
+
+	var (
+		sb    strings.Builder
+		index int
+	)
+	sb.Grow(len(query) + 8)
+	for i := 0; i < len(query); i++ {
+		if end := quotedEnd(query, i); end > i {
+			sb.WriteString(query[i:end])
+			i = end - 1
+			continue
+		}
+		c := query[i]
+		if c == '?' {
+			if i+1 < len(query) && query[i+1] == '?' {
+				sb.WriteByte('?')
+				i++
+				continue
+			}
+			index++
+			sb.WriteString(placeholder(index))
+			continue
+		}
+		sb.WriteByte(c)
+	}
+
+	return sb.String()
+}
+
+// quotedEnd returns the index following the quoted string, quoted identifier or comment
+// starting at query[i], or i if none starts there. An unterminated one ends with the query.
+func quotedEnd(query string, i int) int {
+	var start int
+	var terminator string
+	switch c := query[i]; {
+	case c == '\'' || c == '"' || c == '`':
+		start, terminator = i+1, string(c)
+	case strings.HasPrefix(query[i:], "/*"):
+		start, terminator = i+2, "*/"
+	case strings.HasPrefix(query[i:], "--"):
+		start, terminator = i+2, "\n"
+	default:
+		return i
+	}
+	if j := strings.Index(query[start:], terminator); j >= 0 {
+		return start + j + len(terminator)
+	}
+	return len(query)
+}
+
+// unbind is the reverse of rebind: it rewrites positional placeholders ("$1", "$2", ...)
+// of an already rendered query back into "?" placeholders and reorders the arguments
+// accordingly, so that the query can be embedded into another one and renumbered again.
+// Queries without positional placeholders outside quoted strings, quoted identifiers
+// and comments (e.g. MySQL queries using JSON paths like '$.k') are returned unchanged.
+func unbind(query string, args []interface{}) (string, []interface{}) {
+	if !hasPositional(query, len(args)) {
+		return query, args
+	}
+
+	var (
+		sb      strings.Builder
+		newArgs = make([]interface{}, 0, len(args))
+	)
+	sb.Grow(len(query))
+	for i := 0; i < len(query); i++ {
+		if end := quotedEnd(query, i); end > i {
+			sb.WriteString(query[i:end])
+			i = end - 1
+			continue
+		}
+		switch c := query[i]; c {
+		case '?':
+			sb.WriteString("??")
+			continue
+		case '$':
+			if n, end := positional(query, i, len(args)); n > 0 {
+				sb.WriteByte('?')
+				newArgs = append(newArgs, args[n-1])
+				i = end - 1
+				continue
+			}
+		}
+		sb.WriteByte(query[i])
+	}
+
+	return sb.String(), newArgs
+}
+
+// hasPositional reports whether query has a positional placeholder referring to one
+// of its argc arguments outside quoted strings, quoted identifiers and comments.
+func hasPositional(query string, argc int) bool {
+	if strings.IndexByte(query, '$') < 0 {
+		return false
+	}
+	for i := 0; i < len(query); i++ {
+		if end := quotedEnd(query, i); end > i {
+			i = end - 1
+			continue
+		}
+		if n, _ := positional(query, i, argc); n > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// positional parses the positional placeholder "$n" starting at query[i] and returns n
+// along with the index following it, or 0 if there is none or n is not between 1 and argc.
+// A "$" following an identifier character, as in "a$1", is not a placeholder.
+func positional(query string, i, argc int) (int, int) {
+	if query[i] != '$' || (i > 0 && isIdentChar(query[i-1])) {
+		return 0, i
+	}
+	j := i + 1
+	for j < len(query) && query[j] >= '0' && query[j] <= '9' {
+		j++
+	}
+	if j < len(query) && isIdentChar(query[j]) {
+		return 0, i
+	}
+	n, err := strconv.Atoi(query[i+1 : j])
+	if err != nil || n < 1 || n > argc {
+		return 0, i
+	}
+	return n, j
+}
+
+// isIdentChar reports whether c can be part of an unquoted identifier.
+func isIdentChar(c byte) bool {
+	return c == '_' || c == '$' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
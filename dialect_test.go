@@ -1,6 +1,8 @@
 package builder
 
 import (
+	"errors"
+	"reflect"
 	"testing"
 )
 
@@ -20,7 +22,9 @@ func TestMysqlDialector_Escape(t *testing.T) {
 		{name: "single_field", args: args{[]string{"user"}}, want: "`user`"},
 		{name: "multiple_fields", args: args{[]string{"user", "age", "sex"}}, want: "`user`, `age`, `sex`"},
 		{name: "empty_field", args: args{[]string{""}}, want: "``"},
-		{name: "special_chars", args: args{[]string{"user.name", "table-1", "column_2"}}, want: "`user.name`, `table-1`, `column_2`"},
+		{name: "special_chars", args: args{[]string{"user.name", "table-1", "column_2"}}, want: "`user`.`name`, `table-1`, `column_2`"},
+		{name: "qualified", args: args{[]string{"db.user.name", "u.*", "*"}}, want: "`db`.`user`.`name`, `u`.*, *"},
+		{name: "embedded_quotes", args: args{[]string{"a`b", "x`.`y", `say "hi"`}}, want: "`a``b`, `x```.```y`, `say \"hi\"`"},
 		{name: "with_spaces", args: args{[]string{"first name", "last name"}}, want: "`first name`, `last name`"},
 	}
 	for _, tt := range tests {
@@ -51,7 +55,9 @@ func TestPostgresqlDialector_Escape(t *testing.T) {
 		{name: "single_field", args: args{[]string{"user"}}, want: `"user"`},
 		{name: "multiple_fields", args: args{[]string{"user", "age", "sex"}}, want: `"user", "age", "sex"`},
 		{name: "empty_field", args: args{[]string{""}}, want: `""`},
-		{name: "special_chars", args: args{[]string{"user.name", "table-1", "column_2"}}, want: `"user.name", "table-1", "column_2"`},
+		{name: "special_chars", args: args{[]string{"user.name", "table-1", "column_2"}}, want: `"user"."name", "table-1", "column_2"`},
+		{name: "qualified", args: args{[]string{"db.user.name", "u.*", "*"}}, want: `"db"."user"."name", "u".*, *`},
+		{name: "embedded_quotes", args: args{[]string{`a"b`, `x"."y`, "say `hi`"}}, want: `"a""b", "x"""."""y", "say ` + "`hi`" + `"`},
 		{name: "with_spaces", args: args{[]string{"first name", "last name"}}, want: `"first name", "last name"`},
 	}
 	for _, tt := range tests {
@@ -140,7 +146,9 @@ func TestSQLiteDialector_Escape(t *testing.T) {
 		{name: "single_field", args: args{[]string{"user"}}, want: `"user"`},
 		{name: "multiple_fields", args: args{[]string{"user", "age", "sex"}}, want: `"user", "age", "sex"`},
 		{name: "empty_field", args: args{[]string{""}}, want: `""`},
-		{name: "special_chars", args: args{[]string{"user.name", "table-1", "column_2"}}, want: `"user.name", "table-1", "column_2"`},
+		{name: "special_chars", args: args{[]string{"user.name", "table-1", "column_2"}}, want: `"user"."name", "table-1", "column_2"`},
+		{name: "qualified", args: args{[]string{"db.user.name", "u.*", "*"}}, want: `"db"."user"."name", "u".*, *`},
+		{name: "embedded_quotes", args: args{[]string{`a"b`, `x"."y`, "say `hi`"}}, want: `"a""b", "x"""."""y", "say ` + "`hi`" + `"`},
 		{name: "with_spaces", args: args{[]string{"first name", "last name"}}, want: `"first name", "last name"`},
 	}
 	for _, tt := range tests {
@@ -206,3 +214,279 @@ func TestSQLiteDialector_GetEscapeChar(t *testing.T) {
 		})
 	}
 }
+
+func Test_rebind(t *testing.T) {
+	tests := []struct {
+		name  string
+		d     Dialector
+		query string
+		want  string
+	}{
+		{name: "mysql_unchanged", d: mysqlDialector, query: "a = ? AND b = ?", want: "a = ? AND b = ?"},
+		{name: "mysql_literal", d: mysqlDialector, query: "a = ? AND b ?? 'c??' AND d = ?", want: "a = ? AND b ? 'c??' AND d = ?"},
+		{name: "sqlite_unchanged", d: sqliteDialector, query: "a = ?", want: "a = ?"},
+		{name: "sqlite_literal", d: sqliteDialector, query: "/* ?? */ a ?? b AND c = ?", want: "/* ?? */ a ? b AND c = ?"},
+		{name: "postgres_numbered", d: postgresDialector, query: "a = ? AND b IN (?, ?)", want: "a = $1 AND b IN ($2, $3)"},
+		{name: "postgres_literal", d: postgresDialector, query: "a ?? 'k' AND b = ?", want: "a ? 'k' AND b = $1"},
+		{name: "postgres_quoted", d: postgresDialector, query: `'?' = ? AND "a?" = ? AND 'it''s?' = ?`, want: `'?' = $1 AND "a?" = $2 AND 'it''s?' = $3`},
+		{name: "postgres_comments", d: postgresDialector, query: "/* ? */ a = ? -- ?\nAND b = ?", want: "/* ? */ a = $1 -- ?\nAND b = $2"},
+		{name: "nil_dialector", d: nil, query: "a = ? AND b ?? c", want: "a = ? AND b ? c"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rebind(tt.d, tt.query); got != tt.want {
+				t.Errorf("rebind() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_unbind(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		args     []interface{}
+		want     string
+		wantArgs []interface{}
+	}{
+		{name: "mysql", query: "a = ? AND b = ?", args: []interface{}{1, 2}, want: "a = ? AND b = ?", wantArgs: []interface{}{1, 2}},
+		{name: "postgres", query: "a = $1 AND b = $2", args: []interface{}{1, 2}, want: "a = ? AND b = ?", wantArgs: []interface{}{1, 2}},
+		{name: "reordered", query: "a = $2 AND b = $1 AND c = $2", args: []interface{}{1, 2}, want: "a = ? AND b = ? AND c = ?", wantArgs: []interface{}{2, 1, 2}},
+		{name: "literal", query: `a ? 'k' AND b = $1 AND c = '$1' AND "$1" = $1`, args: []interface{}{1}, want: `a ?? 'k' AND b = ? AND c = '$1' AND "$1" = ?`, wantArgs: []interface{}{1, 1}},
+		{name: "json_path", query: "a = ? AND b = JSON_EXTRACT(doc, '$.k')", args: []interface{}{1}, want: "a = ? AND b = JSON_EXTRACT(doc, '$.k')", wantArgs: []interface{}{1}},
+		{name: "quoted_only", query: "a = ? AND b = '$5' AND c = '$1'", args: []interface{}{1}, want: "a = ? AND b = '$5' AND c = '$1'", wantArgs: []interface{}{1}},
+		{name: "identifier", query: "a$1 = ? AND $1a = ?", args: []interface{}{1, 2}, want: "a$1 = ? AND $1a = ?", wantArgs: []interface{}{1, 2}},
+		{name: "out_of_range", query: "a = ? AND b = $3", args: []interface{}{1}, want: "a = ? AND b = $3", wantArgs: []interface{}{1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotArgs := unbind(tt.query, tt.args)
+			if got != tt.want {
+				t.Errorf("unbind() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+				t.Errorf("unbind() args = %v, want %v", gotArgs, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestDialector_LimitOffset(t *testing.T) {
+	type args struct {
+		limit  string
+		offset string
+	}
+	tests := []struct {
+		name string
+		d    Dialector
+		args args
+		want string
+	}{
+		{name: "mysql_limit", d: mysqlDialector, args: args{"10", ""}, want: "LIMIT 10"},
+		{name: "mysql_limit_offset", d: mysqlDialector, args: args{"10", "20"}, want: "LIMIT 20, 10"},
+		{name: "mysql_offset", d: mysqlDialector, args: args{"", "20"}, want: "LIMIT 20, 18446744073709551615"},
+		{name: "postgres_limit", d: postgresDialector, args: args{"10", ""}, want: "LIMIT 10"},
+		{name: "postgres_limit_offset", d: postgresDialector, args: args{"10", "20"}, want: "LIMIT 10 OFFSET 20"},
+		{name: "postgres_offset", d: postgresDialector, args: args{"", "20"}, want: "OFFSET 20"},
+		{name: "sqlite_limit", d: sqliteDialector, args: args{"10", ""}, want: "LIMIT 10"},
+		{name: "sqlite_limit_offset", d: sqliteDialector, args: args{"?", "?"}, want: "LIMIT ? OFFSET ?"},
+		{name: "sqlite_offset", d: sqliteDialector, args: args{"", "20"}, want: "LIMIT -1 OFFSET 20"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.d.LimitOffset(tt.args.limit, tt.args.offset); got != tt.want {
+				t.Errorf("Dialector.LimitOffset() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDialector_Upsert(t *testing.T) {
+	type args struct {
+		target []string
+		update string
+	}
+	tests := []struct {
+		name    string
+		d       Dialector
+		args    args
+		want    string
+		wantErr bool
+	}{
+		{name: "mysql_update", d: mysqlDialector, args: args{nil, "`a` = ?"}, want: "ON DUPLICATE KEY UPDATE `a` = ?"},
+		{name: "mysql_nothing", d: mysqlDialector, args: args{[]string{"`id`"}, ""}, want: "ON DUPLICATE KEY UPDATE `id` = `id`"},
+		{name: "mysql_nothing_no_target", d: mysqlDialector, args: args{nil, ""}, wantErr: true},
+		{name: "postgres_update", d: postgresDialector, args: args{[]string{`"id"`}, `"a" = ?`}, want: `ON CONFLICT ("id") DO UPDATE SET "a" = ?`},
+		{name: "postgres_nothing", d: postgresDialector, args: args{nil, ""}, want: `ON CONFLICT DO NOTHING`},
+		{name: "postgres_update_no_target", d: postgresDialector, args: args{nil, `"a" = ?`}, wantErr: true},
+		{name: "sqlite_nothing", d: sqliteDialector, args: args{[]string{`"a"`, `"b"`}, ""}, want: `ON CONFLICT ("a", "b") DO NOTHING`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.d.Upsert(tt.args.target, tt.args.update)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Dialector.Upsert() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Dialector.Upsert() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDialector_Excluded(t *testing.T) {
+	tests := []struct {
+		name   string
+		d      Dialector
+		column string
+		want   string
+	}{
+		{name: "mysql", d: mysqlDialector, column: "`name`", want: "VALUES(`name`)"},
+		{name: "postgres", d: postgresDialector, column: `"name"`, want: `EXCLUDED."name"`},
+		{name: "sqlite", d: sqliteDialector, column: `"name"`, want: `EXCLUDED."name"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.d.Excluded(tt.column); got != tt.want {
+				t.Errorf("Dialector.Excluded() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDialector_DistinctFrom(t *testing.T) {
+	tests := []struct {
+		name     string
+		d        Dialector
+		distinct bool
+		want     string
+	}{
+		{name: "mysql_distinct", d: mysqlDialector, distinct: true, want: "NOT (`a` <=> ?)"},
+		{name: "mysql_not_distinct", d: mysqlDialector, want: "`a` <=> ?"},
+		{name: "postgres_distinct", d: postgresDialector, distinct: true, want: `"a" IS DISTINCT FROM ?`},
+		{name: "postgres_not_distinct", d: postgresDialector, want: `"a" IS NOT DISTINCT FROM ?`},
+		{name: "sqlite_distinct", d: sqliteDialector, distinct: true, want: `"a" IS NOT ?`},
+		{name: "sqlite_not_distinct", d: sqliteDialector, want: `"a" IS ?`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.d.DistinctFrom(tt.d.Escape("a"), "?", tt.distinct); got != tt.want {
+				t.Errorf("Dialector.DistinctFrom() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDialector_SetOperation(t *testing.T) {
+	tests := []struct {
+		name     string
+		d        Dialector
+		operator string
+		want     string
+		wantErr  error
+	}{
+		{name: "mysql_union", d: mysqlDialector, operator: "UNION", want: "UNION"},
+		{name: "mysql_union_all", d: mysqlDialector, operator: "UNION ALL", want: "UNION ALL"},
+		{name: "mysql_intersect", d: mysqlDialector, operator: "INTERSECT", wantErr: ErrNotSupported},
+		{name: "mysql_except", d: mysqlDialector, operator: "EXCEPT", wantErr: ErrNotSupported},
+		{name: "postgres_intersect", d: postgresDialector, operator: "INTERSECT", want: "INTERSECT"},
+		{name: "postgres_except", d: postgresDialector, operator: "EXCEPT", want: "EXCEPT"},
+		{name: "sqlite_except", d: sqliteDialector, operator: "EXCEPT", want: "EXCEPT"},
+		{name: "sqlite_unknown", d: sqliteDialector, operator: "MINUS", wantErr: ErrNotSupported},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.d.SetOperation(tt.operator)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Dialector.SetOperation() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Dialector.SetOperation() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDialector_Lock(t *testing.T) {
+	tests := []struct {
+		name     string
+		d        Dialector
+		strength string
+		tables   []string
+		wait     string
+		want     string
+		wantErr  error
+	}{
+		{name: "mysql_update", d: mysqlDialector, strength: "UPDATE", want: "FOR UPDATE"},
+		{name: "mysql_share_of", d: mysqlDialector, strength: "SHARE", tables: []string{"`u`", "`o`"}, wait: "NOWAIT", want: "FOR SHARE OF `u`, `o` NOWAIT"},
+		{name: "postgres_skip_locked", d: postgresDialector, strength: "UPDATE", tables: []string{`"jobs"`}, wait: "SKIP LOCKED", want: `FOR UPDATE OF "jobs" SKIP LOCKED`},
+		{name: "postgres_unknown_strength", d: postgresDialector, strength: "DELETE", wantErr: ErrNotSupported},
+		{name: "postgres_unknown_wait", d: postgresDialector, strength: "UPDATE", wait: "WAIT 5", wantErr: ErrNotSupported},
+		{name: "sqlite", d: sqliteDialector, strength: "UPDATE", wantErr: ErrNotSupported},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.d.Lock(tt.strength, tt.tables, tt.wait)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Dialector.Lock() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Dialector.Lock() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDialector_Returning(t *testing.T) {
+	tests := []struct {
+		name    string
+		d       Dialector
+		columns string
+		want    string
+		wantErr error
+	}{
+		{name: "mysql", d: mysqlDialector, columns: "`id`", wantErr: ErrNotSupported},
+		{name: "postgres", d: postgresDialector, columns: `"id", "name"`, want: `RETURNING "id", "name"`},
+		{name: "sqlite", d: sqliteDialector, columns: "*", want: "RETURNING *"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.d.Returning(tt.columns)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Dialector.Returning() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Dialector.Returning() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDialector_Savepoint(t *testing.T) {
+	tests := []struct {
+		name                         string
+		d                            Dialector
+		savepoint, release, rollback string
+	}{
+		{name: "mysql", d: mysqlDialector, savepoint: "SAVEPOINT `sp_1`", release: "RELEASE SAVEPOINT `sp_1`", rollback: "ROLLBACK TO SAVEPOINT `sp_1`"},
+		{name: "postgres", d: postgresDialector, savepoint: `SAVEPOINT "sp_1"`, release: `RELEASE SAVEPOINT "sp_1"`, rollback: `ROLLBACK TO SAVEPOINT "sp_1"`},
+		{name: "sqlite", d: sqliteDialector, savepoint: `SAVEPOINT "sp_1"`, release: `RELEASE SAVEPOINT "sp_1"`, rollback: `ROLLBACK TO SAVEPOINT "sp_1"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.d.Savepoint("sp_1"); got != tt.savepoint {
+				t.Errorf("Dialector.Savepoint() = %v, want %v", got, tt.savepoint)
+			}
+			if got := tt.d.ReleaseSavepoint("sp_1"); got != tt.release {
+				t.Errorf("Dialector.ReleaseSavepoint() = %v, want %v", got, tt.release)
+			}
+			if got := tt.d.RollbackToSavepoint("sp_1"); got != tt.rollback {
+				t.Errorf("Dialector.RollbackToSavepoint() = %v, want %v", got, tt.rollback)
+			}
+		})
+	}
+}
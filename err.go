@@ -3,6 +3,7 @@ package builder
 
 import (
 	"errors"
+	"fmt"
 )
 
 // Error variables for common SQL builder error conditions.
@@ -17,8 +18,84 @@ var (
 	// specifying the SQL operation type (SELECT, INSERT, etc.).
 	ErrEmptySQLType = errors.New("empty sql type")
 
+	// ErrEmptySubquery is returned when a sub-query is nil or has no SQL to embed.
+	ErrEmptySubquery = errors.New("empty sub-query")
+
+	// ErrEmptyConflictTarget is returned when an upsert needs conflict target columns
+	// (see Builder.OnConflict) but none were given.
+	ErrEmptyConflictTarget = errors.New("empty conflict target")
+
+	// ErrNotSupported is returned when the current SQL dialect does not support
+	// the requested feature, e.g. RETURNING clauses on MySQL.
+	ErrNotSupported = errors.New("not supported by the sql dialect")
+
+	// ErrDialectorChanged is returned when the SQL dialect of a Builder is changed while
+	// it holds a partially built statement, already escaped for the previous dialect.
+	ErrDialectorChanged = errors.New("sql dialect changed after the statement was started")
+
+	// ErrDialectorMismatch is returned when an un-built *Builder used as a sub-query has
+	// another SQL dialect than the outer query, its identifiers being escaped for it.
+	ErrDialectorMismatch = errors.New("sub-query uses another sql dialect")
+
+	// ErrNegativeLimit is returned when a negative limit or offset is given to
+	// Builder.Limit, Builder.Offset or Builder.Page.
+	ErrNegativeLimit = errors.New("negative limit or offset")
+
+	// ErrMisplacedAppend is returned when Builder.Append adds text to a clause followed,
+	// in SQL order, by a clause that is already set, so the text would not end the query.
+	ErrMisplacedAppend = errors.New("appended text would precede a clause already set")
+
+	// ErrInvalidScanDest is returned when a scan destination is not a supported
+	// pointer type (see ScanRow and ScanRows).
+	ErrInvalidScanDest = errors.New("invalid scan destination")
+
+	// ErrColumnMismatch is returned when the columns of a result do not match
+	// the scan destination, e.g. a column without a matching struct field.
+	ErrColumnMismatch = errors.New("columns do not match scan destination")
+
+	// ErrValuesMismatch is wrapped by a *ValuesError, reported when a row of values
+	// is empty or does not have as many values as the columns of an INSERT.
+	ErrValuesMismatch = errors.New("values do not match columns")
+
+	// ErrMissingPrimaryKey is returned when Builder.UpdateStruct is given a struct without
+	// pk fields and no WHERE clause is added to the query.
+	ErrMissingPrimaryKey = errors.New("missing primary key")
+
+	// ErrInvalidIdentifier is returned when an identifier does not match the
+	// validation pattern of the builder (see Builder.SetIdentifierPattern).
+	ErrInvalidIdentifier = errors.New("invalid identifier")
+
+	// ErrEmptyQuery is returned by an Executor when it is given no query to run.
+	ErrEmptyQuery = errors.New("empty query")
+
 	// ErrListIsNotEmpty is returned when there are accumulated errors during
 	// query construction. This typically indicates invalid SQL syntax or
 	// incompatible operations.
 	ErrListIsNotEmpty = errors.New("there are some errors in SQL, please check your query")
 )
+
+// ValuesError is recorded in ErrList by Builder.Values, and returned by Builder.BuildBatch,
+// for a row of values that is empty or does not have as many values as the fields set by
+// Into (or as the first row if there are none). It wraps ErrValuesMismatch.
+//
+// Example:
+//
+//	var verr *builder.ValuesError
+//	if errors.As(b.ErrList[0], &verr) {
+//		log.Printf("row %d: got %d values, want %d", verr.Row, verr.Got, verr.Want)
+//	}
+type ValuesError struct {
+	Row  int // The index of the row, counting the rows of every Values call
+	Got  int // The number of values of the row
+	Want int // The number of fields of the INSERT
+}
+
+// Error implements the error interface.
+func (e *ValuesError) Error() string {
+	return fmt.Sprintf("%s: row %d has %d values, want %d", ErrValuesMismatch, e.Row, e.Got, e.Want)
+}
+
+// Unwrap returns ErrValuesMismatch, so that errors.Is(err, ErrValuesMismatch) reports true.
+func (e *ValuesError) Unwrap() error {
+	return ErrValuesMismatch
+}
@@ -0,0 +1,194 @@
+// Package builder provides a fluent SQL query builder with support for multiple SQL dialects.
+package builder
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// DB is the interface used by an Executor to run statements.
+// It is implemented by *sql.DB, *sql.Tx and *sql.Conn.
+type DB interface {
+	Queryer
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// Result holds the outcome of a statement run by Executor.Exec.
+type Result struct {
+	// LastInsertID is the id generated by the database for an INSERT,
+	// or 0 if the driver does not support it (e.g. PostgreSQL, use Returning instead).
+	LastInsertID int64
+
+	// RowsAffected is the number of rows affected by an INSERT, UPDATE or DELETE,
+	// or 0 if the driver does not support it.
+	RowsAffected int64
+}
+
+// Executor runs queries built by a Builder against a *sql.DB, *sql.Tx or *sql.Conn.
+// Every method accepts either a built *Query or an un-built *Builder, which is built
+// right before running it. Errors collected in the Builder's ErrList are returned
+// without touching the database.
+//
+// Example:
+//
+//	e := builder.NewExecutor(db).SetDialector(builder.PostgresqlDialector{})
+//	res, err := e.Exec(ctx, e.New().Update("users", builder.NewFV("age", 26)).Where(builder.Eq("id", 1)))
+//
+//	var users []User
+//	err = e.Select(ctx, e.New().Select("*").From("users"), &users)
+type Executor struct {
+	// db runs the statements
+	db DB
+	// dialector is used by the builders created with New
+	dialector Dialector
+}
+
+// NewExecutor creates a new Executor for the given *sql.DB, *sql.Tx or *sql.Conn
+// with the default MySQL dialect.
+func NewExecutor(db DB) *Executor {
+	return &Executor{
+		db:        db,
+		dialector: mysqlDialector,
+	}
+}
+
+// SetDialector sets the SQL dialect of the builders created with New.
+// It returns the Executor instance for method chaining.
+func (e *Executor) SetDialector(d Dialector) *Executor {
+	e.dialector = d
+	return e
+}
+
+// DB returns the underlying *sql.DB, *sql.Tx or *sql.Conn, or the *Tx of a transaction.
+func (e *Executor) DB() DB {
+	return e.db
+}
+
+// New creates a new Builder using the dialect of the Executor.
+func (e *Executor) New() *Builder {
+	return New().SetDialector(e.dialector)
+}
+
+// Exec runs a statement that returns no rows, such as an INSERT, UPDATE or DELETE.
+// The query is a built *Query or an un-built *Builder.
+func (e *Executor) Exec(ctx context.Context, query interface{}) (*Result, error) {
+	q, err := toQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	res, err := e.db.ExecContext(ctx, q.Query, q.Args...)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Result{}
+	// Drivers that do not support these return an error, which is not an execution error.
+	if id, err := res.LastInsertId(); err == nil {
+		r.LastInsertID = id
+	}
+	if n, err := res.RowsAffected(); err == nil {
+		r.RowsAffected = n
+	}
+	return r, nil
+}
+
+// ExecBatch runs the given statements in order in a single transaction (see WithTx),
+// typically the queries built by Builder.BuildBatch. The transaction is rolled back
+// if any statement fails. The returned Result holds the total number of affected rows
+// and the LastInsertID of the last statement.
+//
+// Example:
+//
+//	queries, err := e.New().Insert("users", "name", "age").BuildBatch(rows...)
+//	if err != nil {
+//		return err
+//	}
+//	res, err := e.ExecBatch(ctx, queries)
+func (e *Executor) ExecBatch(ctx context.Context, queries []*Query) (*Result, error) {
+	total := &Result{}
+	err := e.WithTx(ctx, nil, func(tx *Tx) error {
+		for _, q := range queries {
+			res, err := tx.Exec(ctx, q)
+			if err != nil {
+				return err
+			}
+			total.LastInsertID = res.LastInsertID
+			total.RowsAffected += res.RowsAffected
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return total, nil
+}
+
+// Query runs a query that returns rows. The caller must close the returned rows.
+// The query is a built *Query or an un-built *Builder.
+func (e *Executor) Query(ctx context.Context, query interface{}) (*sql.Rows, error) {
+	q, err := toQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	return e.db.QueryContext(ctx, q.Query, q.Args...)
+}
+
+// QueryRow runs a query that is expected to return at most one row.
+// Errors of the query itself are deferred until the row's Scan method is called,
+// the returned error only reports an invalid query.
+// The query is a built *Query or an un-built *Builder.
+func (e *Executor) QueryRow(ctx context.Context, query interface{}) (*sql.Row, error) {
+	q, err := toQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	return e.db.QueryRowContext(ctx, q.Query, q.Args...), nil
+}
+
+// Get runs a query and scans its first row into dest (see ScanOne).
+// It returns sql.ErrNoRows if the query returns no rows.
+// The query is a built *Query or an un-built *Builder.
+func (e *Executor) Get(ctx context.Context, query interface{}, dest interface{}) error {
+	q, err := toQuery(query)
+	if err != nil {
+		return err
+	}
+	return ScanOne(ctx, e.db, q, dest)
+}
+
+// Select runs a query and scans all its rows into the slice pointed to by dest (see ScanAll).
+// The query is a built *Query or an un-built *Builder.
+func (e *Executor) Select(ctx context.Context, query interface{}, dest interface{}) error {
+	q, err := toQuery(query)
+	if err != nil {
+		return err
+	}
+	return ScanAll(ctx, e.db, q, dest)
+}
+
+// toQuery returns the *Query to run for a built *Query or an un-built *Builder.
+// The errors collected by a Builder are returned along with ErrListIsNotEmpty.
+func toQuery(query interface{}) (*Query, error) {
+	switch query := query.(type) {
+	case *Query:
+		if query == nil {
+			return nil, ErrEmptyQuery
+		}
+		return query, nil
+	case *Builder:
+		if query == nil {
+			return nil, ErrEmptyQuery
+		}
+		errs := append([]error{}, query.ErrList...)
+		q, err := query.Build()
+		if err == ErrListIsNotEmpty {
+			return nil, fmt.Errorf("%w: %v", err, errs)
+		} else if err != nil {
+			return nil, err
+		}
+		return q, nil
+	}
+	return nil, fmt.Errorf("%w: unsupported query type %T", ErrEmptyQuery, query)
+}
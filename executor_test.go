@@ -0,0 +1,179 @@
+package builder
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestExecutor(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		f, db = newFakeDB()
+		e     = NewExecutor(db).SetDialector(postgresDialector)
+	)
+	defer db.Close()
+
+	if e.DB() != db {
+		t.Errorf("Executor.DB() = %v, want %v", e.DB(), db)
+	}
+
+	ins := `INSERT INTO "user" ("name", "age") VALUES ($1, $2)`
+	f.on(ins, &fakeResult{lastInsertID: 7, rowsAffected: 1})
+	res, err := e.Exec(ctx, e.New().Insert("user", "name", "age").Values([]interface{}{"coder", 25}))
+	if err != nil {
+		t.Fatalf("Executor.Exec() error: %s", err)
+	}
+	if *res != (Result{LastInsertID: 7, RowsAffected: 1}) {
+		t.Errorf("Executor.Exec() = %#v", res)
+	}
+
+	sel := `SELECT "id", "name" FROM "user" WHERE "age" > $1`
+	f.on(sel, &fakeResult{
+		columns: []string{"id", "name"},
+		rows:    [][]driver.Value{{int64(1), "coder"}, {int64(2), "hacker"}},
+	})
+	var users []testUser
+	if err = e.Select(ctx, e.New().Select("id", "name").From("user").Where(Gt("age", 18)), &users); err != nil {
+		t.Fatalf("Executor.Select() error: %s", err)
+	}
+	if len(users) != 2 || users[1].Name != "hacker" {
+		t.Errorf("Executor.Select() = %#v", users)
+	}
+
+	q, _ := e.New().Select("id", "name").From("user").Where(Gt("age", 18)).Build()
+	var user testUser
+	if err = e.Get(ctx, q, &user); err != nil || user.ID != 1 {
+		t.Errorf("Executor.Get() = %#v, error: %v", user, err)
+	}
+
+	rows, err := e.Query(ctx, q)
+	if err != nil {
+		t.Fatalf("Executor.Query() error: %s", err)
+	}
+	var n int
+	for rows.Next() {
+		n++
+	}
+	rows.Close()
+	if n != 2 {
+		t.Errorf("Executor.Query() returned %d rows, want 2", n)
+	}
+
+	row, err := e.QueryRow(ctx, q)
+	if err != nil {
+		t.Fatalf("Executor.QueryRow() error: %s", err)
+	}
+	var (
+		id   int64
+		name string
+	)
+	if err = row.Scan(&id, &name); err != nil || id != 1 || name != "coder" {
+		t.Errorf("Executor.QueryRow() = %d, %s, error: %v", id, name, err)
+	}
+
+	want := []string{
+		`INSERT INTO "user" ("name", "age") VALUES ($1, $2) [coder, 25]`,
+		sel + " [18]",
+		sel + " [18]",
+		sel + " [18]",
+		sel + " [18]",
+	}
+	if got := f.statements(); !reflect.DeepEqual(got, want) {
+		t.Errorf("statements = %#v, want %#v", got, want)
+	}
+}
+
+func TestExecutor_Errors(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		f, db = newFakeDB()
+		e     = NewExecutor(db)
+		dest  []testUser
+	)
+	defer db.Close()
+
+	invalid := func() *Builder {
+		return e.New().Select("*").From("user").Where(errOpCond)
+	}
+	if _, err := e.Exec(ctx, invalid()); !errors.Is(err, ErrListIsNotEmpty) {
+		t.Errorf("Executor.Exec() error = %v, want %v", err, ErrListIsNotEmpty)
+	}
+	if _, err := e.Query(ctx, invalid()); !errors.Is(err, ErrListIsNotEmpty) {
+		t.Errorf("Executor.Query() error = %v, want %v", err, ErrListIsNotEmpty)
+	}
+	if _, err := e.QueryRow(ctx, invalid()); !errors.Is(err, ErrListIsNotEmpty) {
+		t.Errorf("Executor.QueryRow() error = %v, want %v", err, ErrListIsNotEmpty)
+	}
+	if err := e.Get(ctx, invalid(), &dest); !errors.Is(err, ErrListIsNotEmpty) {
+		t.Errorf("Executor.Get() error = %v, want %v", err, ErrListIsNotEmpty)
+	}
+	if err := e.Select(ctx, invalid(), &dest); !errors.Is(err, ErrListIsNotEmpty) {
+		t.Errorf("Executor.Select() error = %v, want %v", err, ErrListIsNotEmpty)
+	}
+	if _, err := e.Exec(ctx, New()); err != ErrEmptySQLType {
+		t.Errorf("Executor.Exec() error = %v, want %v", err, ErrEmptySQLType)
+	}
+	if _, err := e.Exec(ctx, "DELETE FROM user"); !errors.Is(err, ErrEmptyQuery) {
+		t.Errorf("Executor.Exec() error = %v, want %v", err, ErrEmptyQuery)
+	}
+	if _, err := e.Exec(ctx, (*Query)(nil)); !errors.Is(err, ErrEmptyQuery) {
+		t.Errorf("Executor.Exec() error = %v, want %v", err, ErrEmptyQuery)
+	}
+	if got := f.statements(); len(got) != 0 {
+		t.Errorf("statements = %#v, want none", got)
+	}
+
+	f.on("DELETE FROM `user`", &fakeResult{err: errors.New("boom")})
+	if _, err := e.Exec(ctx, e.New().Delete("user")); err == nil || err.Error() != "boom" {
+		t.Errorf("Executor.Exec() error = %v, want boom", err)
+	}
+}
+
+func TestExecutor_ExecBatch(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		f, db = newFakeDB()
+		e     = NewExecutor(db).SetDialector(smallDialector{})
+	)
+	defer db.Close()
+
+	rows := [][]interface{}{{"a", 1}, {"b", 2}, {"c", 3}, {"d", 4}, {"e", 5}}
+	queries, err := e.New().Insert("user", "name", "age").BuildBatch(rows...)
+	if err != nil {
+		t.Fatalf("BuildBatch() error: %s", err)
+	}
+	if len(queries) != 2 {
+		t.Fatalf("BuildBatch() returned %d queries, want 2", len(queries))
+	}
+	f.on(queries[0].Query, &fakeResult{lastInsertID: 3, rowsAffected: 3})
+	f.on(queries[1].Query, &fakeResult{lastInsertID: 5, rowsAffected: 2})
+	res, err := e.ExecBatch(ctx, queries)
+	if err != nil {
+		t.Fatalf("Executor.ExecBatch() error: %s", err)
+	}
+	if *res != (Result{LastInsertID: 5, RowsAffected: 5}) {
+		t.Errorf("Executor.ExecBatch() = %#v", res)
+	}
+
+	f.on(queries[1].Query, &fakeResult{err: errors.New("boom")})
+	if _, err = e.ExecBatch(ctx, queries); err == nil || err.Error() != "boom" {
+		t.Errorf("Executor.ExecBatch() error = %v, want boom", err)
+	}
+
+	want := []string{
+		"BEGIN",
+		`INSERT INTO "user" ("name", "age") VALUES (?, ?), (?, ?), (?, ?) [a, 1, b, 2, c, 3]`,
+		`INSERT INTO "user" ("name", "age") VALUES (?, ?), (?, ?) [d, 4, e, 5]`,
+		"COMMIT",
+		"BEGIN",
+		`INSERT INTO "user" ("name", "age") VALUES (?, ?), (?, ?), (?, ?) [a, 1, b, 2, c, 3]`,
+		`INSERT INTO "user" ("name", "age") VALUES (?, ?), (?, ?) [d, 4, e, 5]`,
+		"ROLLBACK",
+	}
+	if got := f.statements(); !reflect.DeepEqual(got, want) {
+		t.Errorf("statements = %#v, want %#v", got, want)
+	}
+}
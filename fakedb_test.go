@@ -0,0 +1,201 @@
+package builder
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// fakeResult is the result of a statement executed on a fakeDB.
+type fakeResult struct {
+	columns      []string
+	rows         [][]driver.Value
+	lastInsertID int64
+	rowsAffected int64
+	err          error
+}
+
+// fakeDB is an in-process database/sql driver used by the tests.
+// Statements return the result registered for their query string,
+// and every statement along with the transaction events is recorded in log.
+type fakeDB struct {
+	mu      sync.Mutex
+	results map[string]*fakeResult
+	log     []string
+}
+
+// newFakeDB returns a fakeDB and an *sql.DB connected to it.
+func newFakeDB() (*fakeDB, *sql.DB) {
+	f := &fakeDB{results: map[string]*fakeResult{}}
+	return f, sql.OpenDB(f)
+}
+
+// on registers the result of a query string.
+func (f *fakeDB) on(query string, res *fakeResult) *fakeDB {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.results[query] = res
+	return f
+}
+
+// record appends a statement to the log and returns its registered result.
+func (f *fakeDB) record(query string, args []driver.NamedValue) *fakeResult {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	entry := query
+	if len(args) > 0 {
+		vals := make([]string, len(args))
+		for i, arg := range args {
+			vals[i] = fmt.Sprint(arg.Value)
+		}
+		entry += " [" + strings.Join(vals, ", ") + "]"
+	}
+	f.log = append(f.log, entry)
+	if res, ok := f.results[query]; ok {
+		return res
+	}
+	return &fakeResult{}
+}
+
+// statements returns the recorded log.
+func (f *fakeDB) statements() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string{}, f.log...)
+}
+
+// Connect implements driver.Connector.
+func (f *fakeDB) Connect(context.Context) (driver.Conn, error) {
+	return &fakeConn{db: f}, nil
+}
+
+// Driver implements driver.Connector.
+func (f *fakeDB) Driver() driver.Driver {
+	return fakeDriver{f}
+}
+
+type fakeDriver struct {
+	db *fakeDB
+}
+
+func (d fakeDriver) Open(string) (driver.Conn, error) {
+	return &fakeConn{db: d.db}, nil
+}
+
+type fakeConn struct {
+	db *fakeDB
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{conn: c, query: query}, nil
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *fakeConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	c.db.record("BEGIN", nil)
+	return &fakeTx{conn: c}, nil
+}
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	res := c.db.record(query, args)
+	if res.err != nil {
+		return nil, res.err
+	}
+	return fakeExecResult{res}, nil
+}
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	res := c.db.record(query, args)
+	if res.err != nil {
+		return nil, res.err
+	}
+	return &fakeRows{res: res}, nil
+}
+
+type fakeTx struct {
+	conn *fakeConn
+}
+
+func (tx *fakeTx) Commit() error {
+	tx.conn.db.record("COMMIT", nil)
+	return nil
+}
+
+func (tx *fakeTx) Rollback() error {
+	tx.conn.db.record("ROLLBACK", nil)
+	return nil
+}
+
+type fakeStmt struct {
+	conn  *fakeConn
+	query string
+}
+
+func (s *fakeStmt) Close() error {
+	return nil
+}
+
+func (s *fakeStmt) NumInput() int {
+	return -1
+}
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.conn.ExecContext(context.Background(), s.query, namedValues(args))
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.conn.QueryContext(context.Background(), s.query, namedValues(args))
+}
+
+func namedValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+	}
+	return named
+}
+
+type fakeExecResult struct {
+	res *fakeResult
+}
+
+func (r fakeExecResult) LastInsertId() (int64, error) {
+	return r.res.lastInsertID, nil
+}
+
+func (r fakeExecResult) RowsAffected() (int64, error) {
+	return r.res.rowsAffected, nil
+}
+
+type fakeRows struct {
+	res *fakeResult
+	pos int
+}
+
+func (r *fakeRows) Columns() []string {
+	return r.res.columns
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.res.rows) {
+		return io.EOF
+	}
+	copy(dest, r.res.rows[r.pos])
+	r.pos++
+	return nil
+}
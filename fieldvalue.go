@@ -86,3 +86,27 @@ func NewFV(name string, value interface{}) *FieldValue {
 func NewKV(name string, value interface{}) *FieldValue {
 	return NewFieldValue(name, value)
 }
+
+// Excluded references the value proposed for insertion of the given column
+// in the update part of an upsert. It is rendered as EXCLUDED."column" for
+// PostgreSQL and SQLite, and as VALUES(`column`) for MySQL.
+//
+// Example:
+//
+//	b.Insert("users", "id", "name").Values(vals).
+//	  OnConflict("id").
+//	  DoUpdate(NewFV("name", Excluded("name")))
+type Excluded string
+
+// Default is a value rendered as the DEFAULT keyword, so that a column of a
+// Values row or of a SET list takes its default value. SQLite has no DEFAULT
+// keyword, an error is recorded in ErrList there; leave the column out instead.
+//
+// Example:
+//
+//	b.Insert("users", "name", "created_at").Values([]interface{}{"coder", Default})
+//	// Generates: INSERT INTO `users` (`name`, `created_at`) VALUES (?, DEFAULT)
+var Default = defaultValue{}
+
+// defaultValue is the type of Default.
+type defaultValue struct{}
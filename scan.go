@@ -0,0 +1,175 @@
+// Package builder provides a fluent SQL query builder with support for multiple SQL dialects.
+package builder
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// Queryer is the interface used to run queries for scanning.
+// It is implemented by *sql.DB, *sql.Tx and *sql.Conn.
+type Queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+var (
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	timeType    = reflect.TypeOf(time.Time{})
+)
+
+// ScanOne runs the query and scans its first row into dest, which must be a pointer
+// to a struct, a map[string]interface{} or a scalar value (see ScanRow).
+// It returns sql.ErrNoRows if the query returns no rows.
+//
+// Example:
+//
+//	var user User
+//	q, _ := b.Select("*").From("users").Where(builder.Eq("id", 1)).Build()
+//	err := builder.ScanOne(ctx, db, q, &user)
+func ScanOne(ctx context.Context, db Queryer, q *Query, dest interface{}) error {
+	rows, err := db.QueryContext(ctx, q.Query, q.Args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err = rows.Err(); err != nil {
+			return err
+		}
+		return sql.ErrNoRows
+	}
+	if err = ScanRow(rows, dest); err != nil {
+		return err
+	}
+	return rows.Close()
+}
+
+// ScanAll runs the query and scans all rows into dest, which must be a pointer
+// to a slice of structs, pointers to structs, maps or scalar values (see ScanRows).
+//
+// Example:
+//
+//	var users []*User
+//	q, _ := b.Select("*").From("users").Build()
+//	err := builder.ScanAll(ctx, db, q, &users)
+func ScanAll(ctx context.Context, db Queryer, q *Query, dest interface{}) error {
+	rows, err := db.QueryContext(ctx, q.Query, q.Args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	return ScanRows(rows, dest)
+}
+
+// ScanRow scans the current row of rows into dest, which must be a pointer to:
+//   - a struct, whose fields are mapped to the columns through their `db` tags (see TagName)
+//   - a map[string]interface{}, filled with the values of all columns
+//   - a scalar value (or a sql.Scanner), when the row has a single column
+//
+// Every column must have a destination: a column without a matching struct field
+// is reported as ErrColumnMismatch. Struct fields without a column are left untouched.
+func ScanRow(rows *sql.Rows, dest interface{}) error {
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	rv := reflect.ValueOf(dest)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("%w: %T is not a non-nil pointer", ErrInvalidScanDest, dest)
+	}
+	targets, err := scanTargets(rv.Elem(), columns)
+	if err != nil {
+		return err
+	}
+	if err = rows.Scan(targets...); err != nil {
+		return err
+	}
+	if m := rv.Elem(); m.Kind() == reflect.Map {
+		for i, column := range columns {
+			m.SetMapIndex(reflect.ValueOf(column).Convert(m.Type().Key()), reflect.ValueOf(targets[i]).Elem())
+		}
+	}
+	return nil
+}
+
+// ScanRows scans all remaining rows of rows into dest, which must be a pointer to a slice
+// whose elements are structs, pointers to structs, maps or scalar values (see ScanRow).
+// The rows are closed when done.
+func ScanRows(rows *sql.Rows, dest interface{}) error {
+	defer rows.Close()
+
+	rv := reflect.ValueOf(dest)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("%w: %T is not a pointer to a slice", ErrInvalidScanDest, dest)
+	}
+	slice := rv.Elem()
+	elemType := slice.Type().Elem()
+	isPtr := elemType.Kind() == reflect.Ptr
+	if isPtr {
+		elemType = elemType.Elem()
+	}
+
+	result := reflect.MakeSlice(slice.Type(), 0, 0)
+	for rows.Next() {
+		elem := reflect.New(elemType)
+		if err := ScanRow(rows, elem.Interface()); err != nil {
+			return err
+		}
+		if isPtr {
+			result = reflect.Append(result, elem)
+		} else {
+			result = reflect.Append(result, elem.Elem())
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	slice.Set(result)
+	return rows.Close()
+}
+
+// scanTargets returns the pointers passed to sql.Rows.Scan to scan the given columns into v.
+func scanTargets(v reflect.Value, columns []string) ([]interface{}, error) {
+	targets := make([]interface{}, len(columns))
+
+	switch {
+	case v.Kind() == reflect.Map:
+		if v.Type().Key().Kind() != reflect.String || v.Type().Elem().Kind() != reflect.Interface {
+			return nil, fmt.Errorf("%w: %s is not a map[string]interface{}", ErrInvalidScanDest, v.Type())
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+		for i := range columns {
+			targets[i] = new(interface{})
+		}
+	case v.Kind() == reflect.Struct && !isScalarType(v.Type()):
+		info := getStructInfo(v.Type())
+		for i, column := range columns {
+			sf, ok := info.columns[column]
+			if !ok {
+				return nil, fmt.Errorf("%w: column %q has no field in %s", ErrColumnMismatch, column, v.Type())
+			}
+			fv, _ := fieldByIndex(v, sf.index, true)
+			targets[i] = fv.Addr().Interface()
+		}
+	default:
+		if len(columns) != 1 {
+			return nil, fmt.Errorf("%w: scanning %d columns into scalar %s", ErrColumnMismatch, len(columns), v.Type())
+		}
+		targets[0] = v.Addr().Interface()
+	}
+
+	return targets, nil
+}
+
+// isScalarType reports whether a struct type is scanned as a single value,
+// like time.Time or types implementing sql.Scanner.
+func isScalarType(t reflect.Type) bool {
+	return t == timeType || reflect.PtrTo(t).Implements(scannerType)
+}
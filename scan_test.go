@@ -0,0 +1,146 @@
+package builder
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestScanOne(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		now     = time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+		f, db   = newFakeDB()
+		q, _    = New().Select("*").From("user").Where(Eq("id", 1)).Build()
+		cnt, _  = New().Count().From("user").Build()
+		none, _ = New().Select("*").From("user").Where(Eq("name", "nobody")).Build()
+	)
+	defer db.Close()
+	f.on(q.Query, &fakeResult{
+		columns: []string{"id", "name", "email", "age", "created_at"},
+		rows:    [][]driver.Value{{int64(1), "coder", "coder@coder.com", int64(25), now}},
+	})
+	f.on(cnt.Query, &fakeResult{columns: []string{"COUNT(1)"}, rows: [][]driver.Value{{int64(42)}}})
+
+	var user testUser
+	if err := ScanOne(ctx, db, q, &user); err != nil {
+		t.Fatalf("ScanOne() error: %s", err)
+	}
+	want := testUser{ID: 1, Name: "coder", Email: "coder@coder.com", Age: 25, testTimestamps: testTimestamps{CreatedAt: now}}
+	if !reflect.DeepEqual(user, want) {
+		t.Errorf("ScanOne() = %#v, want %#v", user, want)
+	}
+
+	var m map[string]interface{}
+	if err := ScanOne(ctx, db, q, &m); err != nil {
+		t.Fatalf("ScanOne() error: %s", err)
+	}
+	if m["name"] != "coder" || m["age"] != int64(25) || len(m) != 5 {
+		t.Errorf("ScanOne() = %#v", m)
+	}
+
+	var count int
+	if err := ScanOne(ctx, db, cnt, &count); err != nil || count != 42 {
+		t.Errorf("ScanOne() = %d, error: %v", count, err)
+	}
+
+	if err := ScanOne(ctx, db, none, &user); err != sql.ErrNoRows {
+		t.Errorf("ScanOne() error = %v, want %v", err, sql.ErrNoRows)
+	}
+	if err := ScanOne(ctx, db, q, &count); !errors.Is(err, ErrColumnMismatch) {
+		t.Errorf("ScanOne() error = %v, want %v", err, ErrColumnMismatch)
+	}
+	if err := ScanOne(ctx, db, q, user); !errors.Is(err, ErrInvalidScanDest) {
+		t.Errorf("ScanOne() error = %v, want %v", err, ErrInvalidScanDest)
+	}
+
+	type partialUser struct {
+		ID   int64  `db:"id"`
+		Name string `db:"name"`
+	}
+	var partial partialUser
+	if err := ScanOne(ctx, db, q, &partial); !errors.Is(err, ErrColumnMismatch) {
+		t.Errorf("ScanOne() error = %v, want %v", err, ErrColumnMismatch)
+	}
+
+	type Timestamps struct {
+		CreatedAt time.Time `db:"created_at"`
+	}
+	type embeddedUser struct {
+		ID    int64  `db:"id"`
+		Name  string `db:"name"`
+		Email string `db:"email"`
+		Age   int    `db:"age"`
+		*Timestamps
+	}
+	var embedded embeddedUser
+	if err := ScanOne(ctx, db, q, &embedded); err != nil {
+		t.Fatalf("ScanOne() error: %s", err)
+	}
+	if embedded.Timestamps == nil || !embedded.CreatedAt.Equal(now) {
+		t.Errorf("ScanOne() = %#v", embedded)
+	}
+}
+
+func TestScanAll(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		f, db = newFakeDB()
+		q, _  = New().Select("id", "name").From("user").Build()
+		ids   = NewQuery("SELECT `id` FROM `user`")
+	)
+	defer db.Close()
+	f.on(q.Query, &fakeResult{
+		columns: []string{"id", "name"},
+		rows:    [][]driver.Value{{int64(1), "coder"}, {int64(2), "hacker"}},
+	})
+	f.on(ids.Query, &fakeResult{columns: []string{"id"}, rows: [][]driver.Value{{int64(1)}, {int64(2)}}})
+
+	var users []testUser
+	if err := ScanAll(ctx, db, q, &users); err != nil {
+		t.Fatalf("ScanAll() error: %s", err)
+	}
+	if len(users) != 2 || users[0].Name != "coder" || users[1].ID != 2 {
+		t.Errorf("ScanAll() = %#v", users)
+	}
+
+	var ptrs []*testUser
+	if err := ScanAll(ctx, db, q, &ptrs); err != nil {
+		t.Fatalf("ScanAll() error: %s", err)
+	}
+	if len(ptrs) != 2 || ptrs[1].Name != "hacker" {
+		t.Errorf("ScanAll() = %#v", ptrs)
+	}
+
+	var maps []map[string]interface{}
+	if err := ScanAll(ctx, db, q, &maps); err != nil {
+		t.Fatalf("ScanAll() error: %s", err)
+	}
+	want := []map[string]interface{}{{"id": int64(1), "name": "coder"}, {"id": int64(2), "name": "hacker"}}
+	if !reflect.DeepEqual(maps, want) {
+		t.Errorf("ScanAll() = %#v, want %#v", maps, want)
+	}
+
+	var got []int64
+	if err := ScanAll(ctx, db, ids, &got); err != nil || !reflect.DeepEqual(got, []int64{1, 2}) {
+		t.Errorf("ScanAll() = %v, error: %v", got, err)
+	}
+
+	var nullable []sql.NullInt64
+	if err := ScanAll(ctx, db, ids, &nullable); err != nil || len(nullable) != 2 || !nullable[0].Valid {
+		t.Errorf("ScanAll() = %v, error: %v", nullable, err)
+	}
+
+	if err := ScanAll(ctx, db, q, &users[0]); !errors.Is(err, ErrInvalidScanDest) {
+		t.Errorf("ScanAll() error = %v, want %v", err, ErrInvalidScanDest)
+	}
+
+	f.on(q.Query, &fakeResult{err: errors.New("boom")})
+	if err := ScanAll(ctx, db, q, &users); err == nil {
+		t.Errorf("ScanAll() expected error")
+	}
+}
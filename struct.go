@@ -0,0 +1,369 @@
+// Package builder provides a fluent SQL query builder with support for multiple SQL dialects.
+package builder
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// TagName is the struct tag used to map struct fields to database columns.
+//
+// The tag value is the column name optionally followed by comma-separated options:
+//   - omitempty: skip the field when it holds the zero value
+//   - pk: the field is part of the primary key; it is used in the WHERE clause
+//     of UpdateStruct instead of being updated
+//   - readonly: the field is never inserted or updated (e.g. generated columns)
+//
+// A tag value of "-" skips the field. Exported fields without a tag are mapped
+// to the snake_case form of their name, and fields of embedded structs or struct
+// pointers are flattened into the outer struct. The fields of a nil embedded
+// pointer are inserted as NULL and left out of updates, and scanning allocates it.
+// As with encoding/json, when several fields map to the same column the least
+// nested one wins, then the one named by its tag, and the column is skipped if
+// that still leaves more than one field.
+//
+// Example:
+//
+//	type User struct {
+//		ID        int64     `db:"id,pk,omitempty"`
+//		Name      string    `db:"name"`
+//		Email     string    `db:"email,omitempty"`
+//		CreatedAt time.Time `db:"created_at,readonly"`
+//		Password  string    `db:"-"`
+//	}
+const TagName = "db"
+
+// structField holds the column mapping of a single struct field.
+type structField struct {
+	// column is the database column name of the field
+	column string
+	// index is the index sequence of the field for reflect.Value.FieldByIndex
+	index []int
+	// omitEmpty skips the field when it holds the zero value
+	omitEmpty bool
+	// pk marks the field as part of the primary key
+	pk bool
+	// readonly marks the field as never inserted or updated
+	readonly bool
+	// tagged is set when the column name comes from the tag
+	tagged bool
+}
+
+// structInfo holds the column mapping of a struct type.
+type structInfo struct {
+	// fields lists the mapped fields in declaration order
+	fields []*structField
+	// columns maps column names to their fields
+	columns map[string]*structField
+}
+
+// structInfoCache caches the structInfo of each struct type (map[reflect.Type]*structInfo).
+var structInfoCache sync.Map
+
+// getStructInfo returns the cached column mapping of the given struct type.
+func getStructInfo(t reflect.Type) *structInfo {
+	if info, ok := structInfoCache.Load(t); ok {
+		return info.(*structInfo)
+	}
+	info := &structInfo{columns: map[string]*structField{}}
+	parseStructFields(t, nil, info, map[reflect.Type]bool{t: true})
+	info.resolveColumns()
+	actual, _ := structInfoCache.LoadOrStore(t, info)
+	return actual.(*structInfo)
+}
+
+// parseStructFields adds the mapped fields of t to info in declaration order, flattening
+// embedded structs and struct pointers, except the types being flattened (visiting).
+// Fields mapped to the same column are resolved by resolveColumns.
+func parseStructFields(t reflect.Type, index []int, info *structInfo, visiting map[reflect.Type]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, hasTag := f.Tag.Lookup(TagName)
+		if tag == "-" {
+			continue
+		}
+		fieldIndex := append(append([]int{}, index...), i)
+		if ft := f.Type; f.Anonymous && !hasTag {
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				// Like encoding/json, unexported embedded pointers are ignored
+				// since they cannot be allocated when scanning.
+				if !visiting[ft] && (f.PkgPath == "" || f.Type.Kind() != reflect.Ptr) {
+					visiting[ft] = true
+					parseStructFields(ft, fieldIndex, info, visiting)
+					delete(visiting, ft)
+				}
+				continue
+			}
+		}
+		if f.PkgPath != "" { // unexported
+			continue
+		}
+
+		sf := &structField{index: fieldIndex}
+		opts := strings.Split(tag, ",")
+		sf.column = strings.TrimSpace(opts[0])
+		for _, opt := range opts[1:] {
+			switch strings.TrimSpace(opt) {
+			case "omitempty":
+				sf.omitEmpty = true
+			case "pk":
+				sf.pk = true
+			case "readonly":
+				sf.readonly = true
+			}
+		}
+		sf.tagged = sf.column != ""
+		if !sf.tagged {
+			sf.column = toSnakeCase(f.Name)
+		}
+		info.fields = append(info.fields, sf)
+	}
+}
+
+// resolveColumns keeps the dominant field of each column (see dominantField) and indexes
+// the kept fields by column.
+func (info *structInfo) resolveColumns() {
+	byColumn := make(map[string][]*structField, len(info.fields))
+	for _, sf := range info.fields {
+		byColumn[sf.column] = append(byColumn[sf.column], sf)
+	}
+	fields := make([]*structField, 0, len(info.fields))
+	for _, sf := range info.fields {
+		if dominantField(byColumn[sf.column]) == sf {
+			fields = append(fields, sf)
+			info.columns[sf.column] = sf
+		}
+	}
+	info.fields = fields
+}
+
+// dominantField returns the field mapped to a column among the given ones, following the
+// rules of encoding/json: the least nested field wins, then the tagged one at the same depth.
+// It returns nil if several fields are left.
+func dominantField(fields []*structField) *structField {
+	var (
+		dominant  *structField
+		ambiguous bool
+	)
+	for _, sf := range fields {
+		switch {
+		case dominant == nil || len(sf.index) < len(dominant.index),
+			len(sf.index) == len(dominant.index) && sf.tagged && !dominant.tagged:
+			dominant, ambiguous = sf, false
+		case len(sf.index) == len(dominant.index) && sf.tagged == dominant.tagged:
+			ambiguous = true
+		}
+	}
+	if ambiguous {
+		return nil
+	}
+	return dominant
+}
+
+// fieldByIndex returns the nested field of v with the given index sequence like
+// reflect.Value.FieldByIndex, or false if it is reached through a nil embedded pointer.
+// If alloc is set, nil embedded pointers are allocated instead.
+func fieldByIndex(v reflect.Value, index []int, alloc bool) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !alloc {
+					return reflect.Value{}, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// toSnakeCase converts a Go field name into snake_case, e.g. "UserID" to "user_id".
+func toSnakeCase(name string) string {
+	runes := []rune(name)
+	var sb strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				sb.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// structValue dereferences v and checks that it holds a struct.
+func structValue(v interface{}) (reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return reflect.Value{}, fmt.Errorf("invalid struct value: nil %s", rv.Type())
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("invalid struct value: %T", v)
+	}
+	return rv, nil
+}
+
+// InsertStruct begins an INSERT query for the specified table using the fields
+// of the given struct (or pointer to struct), mapped through their `db` tags.
+// Readonly fields and empty omitempty fields are skipped.
+// It produces the same output as Insert(...).Values(...).
+// It returns the Builder instance for method chaining.
+//
+// Example:
+//
+//	b.InsertStruct("users", &User{Name: "coder"})
+//	// Generates: INSERT INTO `users` (`name`) VALUES (?)
+func (b *Builder) InsertStruct(tableName string, v interface{}) *Builder {
+	return b.InsertStructs(tableName, []interface{}{v})
+}
+
+// InsertStructs begins a multi-row INSERT query for the specified table using the
+// elements of the given slice of structs (or pointers to structs), mapped through
+// their `db` tags. Readonly fields are skipped, and omitempty fields are only
+// skipped when they are empty in every row so that all rows share the same columns.
+// It returns the Builder instance for method chaining.
+//
+// Example:
+//
+//	b.InsertStructs("users", []User{{Name: "coder"}, {Name: "hacker"}})
+//	// Generates: INSERT INTO `users` (`name`) VALUES (?), (?)
+func (b *Builder) InsertStructs(tableName string, slice interface{}) *Builder {
+	b.Insert(tableName)
+
+	sv := reflect.ValueOf(slice)
+	if sv.Kind() != reflect.Slice && sv.Kind() != reflect.Array {
+		b.ErrList = append(b.ErrList, fmt.Errorf("invalid struct slice: %T", slice))
+		return b
+	}
+	if sv.Len() <= 0 {
+		b.ErrList = append(b.ErrList, fmt.Errorf("invalid struct slice: empty %T", slice))
+		return b
+	}
+
+	var (
+		rows = make([]reflect.Value, sv.Len())
+		info *structInfo
+	)
+	for i := range rows {
+		rv, err := structValue(sv.Index(i).Interface())
+		if err != nil {
+			b.ErrList = append(b.ErrList, err)
+			return b
+		}
+		if i == 0 {
+			info = getStructInfo(rv.Type())
+		} else if rv.Type() != rows[0].Type() {
+			b.ErrList = append(b.ErrList, fmt.Errorf("invalid struct slice: mixed types %s and %s", rows[0].Type(), rv.Type()))
+			return b
+		}
+		rows[i] = rv
+	}
+
+	fields := make([]*structField, 0, len(info.fields))
+	for _, sf := range info.fields {
+		if sf.readonly {
+			continue
+		}
+		if sf.omitEmpty {
+			empty := true
+			for _, rv := range rows {
+				if fv, ok := fieldByIndex(rv, sf.index, false); ok && !fv.IsZero() {
+					empty = false
+					break
+				}
+			}
+			if empty {
+				continue
+			}
+		}
+		fields = append(fields, sf)
+	}
+	if len(fields) <= 0 {
+		b.ErrList = append(b.ErrList, fmt.Errorf("no columns to insert for %s", rows[0].Type()))
+		return b
+	}
+
+	columns := make([]string, len(fields))
+	for i, sf := range fields {
+		columns[i] = sf.column
+	}
+	valsGroup := make([][]interface{}, len(rows))
+	for i, rv := range rows {
+		vals := make([]interface{}, len(fields))
+		for j, sf := range fields {
+			if fv, ok := fieldByIndex(rv, sf.index, false); ok {
+				vals[j] = fv.Interface()
+			}
+		}
+		valsGroup[i] = vals
+	}
+
+	return b.Into(columns...).Values(valsGroup...)
+}
+
+// UpdateStruct begins an UPDATE query for the specified table using the fields of
+// the given struct (or pointer to struct), mapped through their `db` tags.
+// Readonly fields and empty omitempty fields are skipped, and pk fields are used
+// for the WHERE clause instead of being updated. More conditions can be chained with And.
+// Without pk fields, the WHERE clause must be added by the caller, otherwise
+// ErrMissingPrimaryKey is recorded in ErrList on Build.
+// It produces the same output as Update(...).Set(...).Where(...).
+// It returns the Builder instance for method chaining.
+//
+// Example:
+//
+//	b.UpdateStruct("users", &User{ID: 1, Name: "coder"})
+//	// Generates: UPDATE `users` SET `name` = ? WHERE `id` = ?
+func (b *Builder) UpdateStruct(tableName string, v interface{}) *Builder {
+	b.Update(tableName)
+
+	rv, err := structValue(v)
+	if err != nil {
+		b.ErrList = append(b.ErrList, err)
+		return b
+	}
+
+	var (
+		info  = getStructInfo(rv.Type())
+		fvals = make([]*FieldValue, 0, len(info.fields))
+		conds []*Condition
+	)
+	for _, sf := range info.fields {
+		fv, ok := fieldByIndex(rv, sf.index, false)
+		if !ok {
+			continue
+		}
+		switch {
+		case sf.pk:
+			conds = append(conds, And(sf.column, "=", fv.Interface()))
+		case sf.readonly, sf.omitEmpty && fv.IsZero():
+		default:
+			fvals = append(fvals, NewFieldValue(sf.column, fv.Interface()))
+		}
+	}
+	if len(fvals) <= 0 {
+		b.ErrList = append(b.ErrList, fmt.Errorf("no columns to update for %s", rv.Type()))
+		return b
+	}
+
+	b.Set(fvals...)
+	if len(conds) <= 0 {
+		b.whereRequired = fmt.Errorf("%w: %s", ErrMissingPrimaryKey, rv.Type())
+		return b
+	}
+	return b.Where(conds...)
+}
@@ -0,0 +1,287 @@
+package builder
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testTimestamps struct {
+	CreatedAt time.Time `db:"created_at,readonly"`
+	UpdatedAt time.Time `db:"updated_at,omitempty"`
+}
+
+type testUser struct {
+	ID       int64  `db:"id,pk,omitempty"`
+	Name     string `db:"name"`
+	Email    string `db:"email,omitempty"`
+	Age      int
+	Password string `db:"-"`
+	secret   string
+	testTimestamps
+}
+
+func TestInsertStruct(t *testing.T) {
+	var (
+		got, want      string
+		args, wantArgs []interface{}
+		err            error
+		q              *Query
+	)
+
+	want = "INSERT INTO `user` (`name`, `age`) VALUES (?, ?)"
+	wantArgs = []interface{}{"coder", 25}
+	q, err = b.InsertStruct("user", &testUser{Name: "coder", Age: 25, Password: "x", secret: "y"}).Build()
+	got = q.Query
+	args = q.Args
+	if err != nil {
+		t.Errorf("error: %s", err)
+	}
+	if want != got {
+		t.Errorf("\ngot:\n%s\nwant:\n%s\n", got, want)
+	}
+	if !reflect.DeepEqual(wantArgs, args) {
+		t.Errorf("\ngotArgs:\n%#v\nwantArgs:\n%#v\n", args, wantArgs)
+	}
+
+	// same output as Insert(...).Values(...)
+	manual, _ := b.Insert("user", "name", "age").Values([]interface{}{"coder", 25}).Build()
+	if manual.Query != got {
+		t.Errorf("\ngot:\n%s\nwant:\n%s\n", got, manual.Query)
+	}
+
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	want = "INSERT INTO `user` (`id`, `name`, `email`, `age`, `updated_at`) VALUES (?, ?, ?, ?, ?), (?, ?, ?, ?, ?)"
+	wantArgs = []interface{}{
+		int64(0), "coder", "coder@coder.com", 25, time.Time{},
+		int64(2), "hacker", "", 30, now,
+	}
+	users := []testUser{
+		{Name: "coder", Email: "coder@coder.com", Age: 25},
+		{ID: 2, Name: "hacker", Age: 30, testTimestamps: testTimestamps{UpdatedAt: now}},
+	}
+	q, err = b.InsertStructs("user", users).Build()
+	got = q.Query
+	args = q.Args
+	if err != nil {
+		t.Errorf("error: %s", err)
+	}
+	if want != got {
+		t.Errorf("\ngot:\n%s\nwant:\n%s\n", got, want)
+	}
+	if !reflect.DeepEqual(wantArgs, args) {
+		t.Errorf("\ngotArgs:\n%#v\nwantArgs:\n%#v\n", args, wantArgs)
+	}
+
+	for _, v := range []interface{}{nil, 1, (*testUser)(nil), []int{1}, []testUser{}, []interface{}{testUser{}, &testTimestamps{}}} {
+		if _, err = b.InsertStructs("user", v).Build(); err == nil {
+			t.Errorf("expected error for %#v", v)
+		}
+	}
+}
+
+func TestUpdateStruct(t *testing.T) {
+	var (
+		got, want      string
+		args, wantArgs []interface{}
+		err            error
+		q              *Query
+	)
+
+	want = "UPDATE `user` SET `name` = ?, `age` = ? WHERE `id` = ? AND `age` > ?"
+	wantArgs = []interface{}{"coder", 25, int64(1), 18}
+	q, err = b.UpdateStruct("user", testUser{ID: 1, Name: "coder", Age: 25}).And(Gt("age", 18)).Build()
+	got = q.Query
+	args = q.Args
+	if err != nil {
+		t.Errorf("error: %s", err)
+	}
+	if want != got {
+		t.Errorf("\ngot:\n%s\nwant:\n%s\n", got, want)
+	}
+	if !reflect.DeepEqual(wantArgs, args) {
+		t.Errorf("\ngotArgs:\n%#v\nwantArgs:\n%#v\n", args, wantArgs)
+	}
+
+	type noKey struct {
+		Name string `db:"name"`
+	}
+	if _, err = b.UpdateStruct("user", noKey{Name: "coder"}).Build(); err != ErrListIsNotEmpty {
+		t.Errorf("error = %v, want %v", err, ErrListIsNotEmpty)
+	}
+	want = "UPDATE `user` SET `name` = ? WHERE `id` = ?"
+	q, err = b.UpdateStruct("user", noKey{Name: "coder"}).Where(Eq("id", 1)).Build()
+	if err != nil {
+		t.Errorf("error: %s", err)
+	}
+	if q.Query != want {
+		t.Errorf("\ngot:\n%s\nwant:\n%s\n", q.Query, want)
+	}
+
+	type noPK struct {
+		Name string `db:"name,readonly"`
+	}
+	if _, err = b.UpdateStruct("user", noPK{Name: "coder"}).Build(); err == nil {
+		t.Errorf("expected error for struct without updatable columns")
+	}
+	if _, err = b.UpdateStruct("user", "coder").Build(); err == nil {
+		t.Errorf("expected error for non-struct value")
+	}
+}
+
+func Test_toSnakeCase(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "ID", want: "id"},
+		{name: "UserID", want: "user_id"},
+		{name: "CreatedAt", want: "created_at"},
+		{name: "HTTPServer", want: "http_server"},
+		{name: "Address2", want: "address2"},
+		{name: "name", want: "name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := toSnakeCase(tt.name); got != tt.want {
+				t.Errorf("toSnakeCase() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_getStructInfo(t *testing.T) {
+	typ := reflect.TypeOf(testUser{})
+	info := getStructInfo(typ)
+	if cached := getStructInfo(typ); cached != info {
+		t.Errorf("getStructInfo() is not cached")
+	}
+	var columns []string
+	for _, sf := range info.fields {
+		columns = append(columns, sf.column)
+	}
+	want := []string{"id", "name", "email", "age", "created_at", "updated_at"}
+	if !reflect.DeepEqual(columns, want) {
+		t.Errorf("getStructInfo() columns = %v, want %v", columns, want)
+	}
+	if sf := info.columns["id"]; sf == nil || !sf.pk || !sf.omitEmpty || sf.readonly {
+		t.Errorf("getStructInfo() id = %#v", sf)
+	}
+	if sf := info.columns["created_at"]; sf == nil || !reflect.DeepEqual(sf.index, []int{6, 0}) || !sf.readonly {
+		t.Errorf("getStructInfo() created_at = %#v", sf)
+	}
+}
+
+func Test_getStructInfo_shadowing(t *testing.T) {
+	type base struct {
+		ID    int    `db:"id,pk"`
+		Name  string `db:"name"`
+		Title string
+		Note  string `db:"note"`
+	}
+	type other struct {
+		Title string `db:"title"`
+		Note  string `db:"note"`
+	}
+	type outer struct {
+		base
+		other
+		ID int `db:"id"`
+	}
+	info := getStructInfo(reflect.TypeOf(outer{}))
+	var columns []string
+	for _, sf := range info.fields {
+		columns = append(columns, sf.column)
+	}
+	// the outer id shadows the embedded one, the tagged title wins, note is ambiguous
+	want := []string{"name", "title", "id"}
+	if !reflect.DeepEqual(columns, want) {
+		t.Errorf("getStructInfo() columns = %v, want %v", columns, want)
+	}
+	if sf := info.columns["id"]; sf == nil || !reflect.DeepEqual(sf.index, []int{2}) || sf.pk {
+		t.Errorf("getStructInfo() id = %#v", sf)
+	}
+	if sf := info.columns["title"]; sf == nil || !reflect.DeepEqual(sf.index, []int{1, 0}) {
+		t.Errorf("getStructInfo() title = %#v", sf)
+	}
+
+	q, err := New().InsertStruct("user", outer{base: base{ID: 1, Name: "coder"}, other: other{Title: "dev"}, ID: 2}).Build()
+	if err != nil {
+		t.Errorf("error: %s", err)
+	}
+	if want := "INSERT INTO `user` (`name`, `title`, `id`) VALUES (?, ?, ?)"; q.Query != want {
+		t.Errorf("\ngot:\n%s\nwant:\n%s\n", q.Query, want)
+	}
+	if wantArgs := []interface{}{"coder", "dev", 2}; !reflect.DeepEqual(q.Args, wantArgs) {
+		t.Errorf("\ngotArgs:\n%#v\nwantArgs:\n%#v\n", q.Args, wantArgs)
+	}
+}
+
+func Test_getStructInfo_embeddedPointer(t *testing.T) {
+	type Audit struct {
+		CreatedBy string `db:"created_by"`
+	}
+	type Post struct {
+		ID    int64  `db:"id,pk"`
+		Title string `db:"title"`
+		*Audit
+	}
+	type Node struct {
+		Name string `db:"name"`
+		*Node
+	}
+	info := getStructInfo(reflect.TypeOf(Post{}))
+	var columns []string
+	for _, sf := range info.fields {
+		columns = append(columns, sf.column)
+	}
+	if want := []string{"id", "title", "created_by"}; !reflect.DeepEqual(columns, want) {
+		t.Errorf("getStructInfo() columns = %v, want %v", columns, want)
+	}
+	if sf := info.columns["created_by"]; sf == nil || !reflect.DeepEqual(sf.index, []int{2, 0}) {
+		t.Errorf("getStructInfo() created_by = %#v", sf)
+	}
+	if info := getStructInfo(reflect.TypeOf(Node{})); len(info.fields) != 1 || info.fields[0].column != "name" {
+		t.Errorf("getStructInfo() fields = %#v", info.fields)
+	}
+
+	tests := []struct {
+		name     string
+		q        *Builder
+		want     string
+		wantArgs []interface{}
+	}{
+		{
+			name:     "insert",
+			q:        New().InsertStructs("post", []Post{{ID: 1, Title: "a", Audit: &Audit{CreatedBy: "coder"}}, {ID: 2, Title: "b"}}),
+			want:     "INSERT INTO `post` (`id`, `title`, `created_by`) VALUES (?, ?, ?), (?, ?, ?)",
+			wantArgs: []interface{}{int64(1), "a", "coder", int64(2), "b", nil},
+		},
+		{
+			name:     "update",
+			q:        New().UpdateStruct("post", Post{ID: 1, Title: "a", Audit: &Audit{CreatedBy: "coder"}}),
+			want:     "UPDATE `post` SET `title` = ?, `created_by` = ? WHERE `id` = ?",
+			wantArgs: []interface{}{"a", "coder", int64(1)},
+		},
+		{
+			name:     "update_nil",
+			q:        New().UpdateStruct("post", Post{ID: 1, Title: "a"}),
+			want:     "UPDATE `post` SET `title` = ? WHERE `id` = ?",
+			wantArgs: []interface{}{"a", int64(1)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := tt.q.Build()
+			if err != nil {
+				t.Fatalf("error: %s", err)
+			}
+			if q.Query != tt.want {
+				t.Errorf("\ngot:\n%s\nwant:\n%s\n", q.Query, tt.want)
+			}
+			if !reflect.DeepEqual(q.Args, tt.wantArgs) {
+				t.Errorf("\ngotArgs:\n%#v\nwantArgs:\n%#v\n", q.Args, tt.wantArgs)
+			}
+		})
+	}
+}
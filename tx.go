@@ -0,0 +1,143 @@
+// Package builder provides a fluent SQL query builder with support for multiple SQL dialects.
+package builder
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+)
+
+// TxBeginner is the interface used to start transactions.
+// It is implemented by *sql.DB and *sql.Conn.
+type TxBeginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// Tx is a transaction started by WithTx. It embeds an Executor running on the
+// transaction, so builders created with New and queries run through Exec, Query,
+// QueryRow, Get and Select all take part in the transaction.
+// Calling WithTx on a Tx nests a transaction using savepoints.
+type Tx struct {
+	*Executor
+	// tx is the underlying database transaction
+	tx *sql.Tx
+	// depth is the nesting level, 0 for the outermost transaction
+	depth int
+}
+
+// WithTx runs fn in a transaction started on db, which is a *sql.DB or *sql.Conn, with
+// the SQL dialect d of the database, or MySQL if d is nil. The transaction is committed
+// if fn returns nil, and rolled back if fn returns an error or panics (the panic is then re-raised).
+// If db is a *Tx or a *sql.Tx, the transaction is nested using savepoints instead,
+// keeping the dialect of an outer *Tx. It is a shorthand for
+// NewExecutor(db).SetDialector(d).WithTx(ctx, opts, fn).
+//
+// Example:
+//
+//	err := builder.WithTx(ctx, db, builder.PostgresqlDialector{}, nil, func(tx *builder.Tx) error {
+//		if _, err := tx.Exec(ctx, tx.New().Insert("users", "name").Values([]interface{}{"coder"})); err != nil {
+//			return err
+//		}
+//		return tx.WithTx(ctx, nil, func(tx *builder.Tx) error { // SAVEPOINT "sp_1"
+//			_, err := tx.Exec(ctx, tx.New().Delete("sessions"))
+//			return err // ROLLBACK TO SAVEPOINT "sp_1" on error
+//		})
+//	})
+func WithTx(ctx context.Context, db DB, d Dialector, opts *sql.TxOptions, fn func(tx *Tx) error) error {
+	if d == nil {
+		d = mysqlDialector
+	}
+	return NewExecutor(db).SetDialector(d).WithTx(ctx, opts, fn)
+}
+
+// WithTx runs fn in a transaction started on the database of the Executor,
+// whose dialect is used by the transaction (see the WithTx function).
+// If the Executor runs on a *sql.Tx, the transaction is nested using savepoints.
+func (e *Executor) WithTx(ctx context.Context, opts *sql.TxOptions, fn func(tx *Tx) error) (err error) {
+	var sqlTx *sql.Tx
+	switch db := e.db.(type) {
+	case *Tx:
+		return db.WithTx(ctx, opts, fn)
+	case *sql.Tx:
+		return (&Tx{Executor: e, tx: db}).WithTx(ctx, opts, fn)
+	case TxBeginner:
+		if sqlTx, err = db.BeginTx(ctx, opts); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("transactions on %T: %w", e.db, ErrNotSupported)
+	}
+
+	tx := newTx(sqlTx, e.dialector, 0)
+	defer func() {
+		if p := recover(); p != nil {
+			_ = sqlTx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err = fn(tx); err != nil {
+		if rbErr := sqlTx.Rollback(); rbErr != nil {
+			return fmt.Errorf("%w (rollback: %v)", err, rbErr)
+		}
+		return err
+	}
+	return sqlTx.Commit()
+}
+
+// newTx returns a Tx running on sqlTx at the given nesting depth. Its Executor runs on
+// the Tx itself, so that transactions started through it, e.g. by ExecBatch, are nested.
+func newTx(sqlTx *sql.Tx, d Dialector, depth int) *Tx {
+	tx := &Tx{tx: sqlTx, depth: depth}
+	tx.Executor = NewExecutor(tx).SetDialector(d)
+	return tx
+}
+
+// WithTx runs fn in a transaction nested in tx, using a savepoint rendered by the
+// dialect of tx. The savepoint is released if fn returns nil, and rolled back to
+// if fn returns an error or panics (the panic is then re-raised), leaving the
+// outer transaction usable. The options are ignored for nested transactions.
+func (tx *Tx) WithTx(ctx context.Context, opts *sql.TxOptions, fn func(tx *Tx) error) (err error) {
+	name := "sp_" + strconv.Itoa(tx.depth+1)
+	if _, err = tx.tx.ExecContext(ctx, tx.dialector.Savepoint(name)); err != nil {
+		return err
+	}
+
+	nested := newTx(tx.tx, tx.dialector, tx.depth+1)
+	defer func() {
+		if p := recover(); p != nil {
+			_, _ = tx.tx.ExecContext(ctx, tx.dialector.RollbackToSavepoint(name))
+			panic(p)
+		}
+	}()
+
+	if err = fn(nested); err != nil {
+		if _, rbErr := tx.tx.ExecContext(ctx, tx.dialector.RollbackToSavepoint(name)); rbErr != nil {
+			return fmt.Errorf("%w (rollback: %v)", err, rbErr)
+		}
+		return err
+	}
+	_, err = tx.tx.ExecContext(ctx, tx.dialector.ReleaseSavepoint(name))
+	return err
+}
+
+// Tx returns the underlying *sql.Tx.
+func (tx *Tx) Tx() *sql.Tx {
+	return tx.tx
+}
+
+// ExecContext runs a raw statement on the transaction, so that a *Tx can be used as a DB.
+func (tx *Tx) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return tx.tx.ExecContext(ctx, query, args...)
+}
+
+// QueryContext runs a raw query on the transaction, so that a *Tx can be used as a DB.
+func (tx *Tx) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return tx.tx.QueryContext(ctx, query, args...)
+}
+
+// QueryRowContext runs a raw query on the transaction, so that a *Tx can be used as a DB.
+func (tx *Tx) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return tx.tx.QueryRowContext(ctx, query, args...)
+}
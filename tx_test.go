@@ -0,0 +1,152 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestWithTx(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		f, db = newFakeDB()
+		boom  = errors.New("boom")
+	)
+	defer db.Close()
+
+	err := NewExecutor(db).SetDialector(postgresDialector).WithTx(ctx, nil, func(tx *Tx) error {
+		if tx.Tx() == nil {
+			t.Errorf("Tx.Tx() is nil")
+		}
+		if _, err := tx.Exec(ctx, tx.New().Insert("user", "name").Values([]interface{}{"coder"})); err != nil {
+			return err
+		}
+		// a failing nested transaction is rolled back to its savepoint only
+		err := tx.WithTx(ctx, nil, func(tx *Tx) error {
+			if _, err := tx.Exec(ctx, tx.New().Delete("session")); err != nil {
+				return err
+			}
+			return WithTx(ctx, tx, postgresDialector, nil, func(tx *Tx) error {
+				return boom
+			})
+		})
+		if err != boom {
+			t.Errorf("nested WithTx() error = %v, want %v", err, boom)
+		}
+		return WithTx(ctx, tx, postgresDialector, nil, func(tx *Tx) error {
+			_, err := tx.Exec(ctx, tx.New().Update("user", NewFV("age", 26)).Where(Eq("id", 1)))
+			return err
+		})
+	})
+	if err != nil {
+		t.Fatalf("WithTx() error: %s", err)
+	}
+
+	want := []string{
+		"BEGIN",
+		`INSERT INTO "user" ("name") VALUES ($1) [coder]`,
+		`SAVEPOINT "sp_1"`,
+		`DELETE FROM "session"`,
+		`SAVEPOINT "sp_2"`,
+		`ROLLBACK TO SAVEPOINT "sp_2"`,
+		`ROLLBACK TO SAVEPOINT "sp_1"`,
+		`SAVEPOINT "sp_1"`,
+		`UPDATE "user" SET "age" = $1 WHERE "id" = $2 [26, 1]`,
+		`RELEASE SAVEPOINT "sp_1"`,
+		"COMMIT",
+	}
+	if got := f.statements(); !reflect.DeepEqual(got, want) {
+		t.Errorf("statements = %#v, want %#v", got, want)
+	}
+}
+
+func TestWithTx_Rollback(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		f, db = newFakeDB()
+		boom  = errors.New("boom")
+	)
+	defer db.Close()
+
+	err := WithTx(ctx, db, sqliteDialector, nil, func(tx *Tx) error {
+		_, err := tx.Exec(ctx, tx.New().Delete("user"))
+		if err != nil {
+			return err
+		}
+		return boom
+	})
+	if err != boom {
+		t.Errorf("WithTx() error = %v, want %v", err, boom)
+	}
+
+	func() {
+		defer func() {
+			if p := recover(); p != "panic" {
+				t.Errorf("WithTx() recovered %v, want panic", p)
+			}
+		}()
+		_ = WithTx(ctx, db, sqliteDialector, nil, func(tx *Tx) error {
+			return tx.WithTx(ctx, nil, func(tx *Tx) error {
+				panic("panic")
+			})
+		})
+	}()
+
+	want := []string{
+		"BEGIN",
+		`DELETE FROM "user"`,
+		"ROLLBACK",
+		"BEGIN",
+		`SAVEPOINT "sp_1"`,
+		`ROLLBACK TO SAVEPOINT "sp_1"`,
+		"ROLLBACK",
+	}
+	if got := f.statements(); !reflect.DeepEqual(got, want) {
+		t.Errorf("statements = %#v, want %#v", got, want)
+	}
+
+	if err = NewExecutor(struct{ DB }{db}).WithTx(ctx, nil, nil); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("WithTx() error = %v, want %v", err, ErrNotSupported)
+	}
+}
+
+func TestWithTx_Nested(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		f, db = newFakeDB()
+	)
+	defer db.Close()
+
+	// batches and executors run on a transaction nest their own transactions
+	err := WithTx(ctx, db, nil, nil, func(tx *Tx) error {
+		return tx.WithTx(ctx, nil, func(tx *Tx) error {
+			if _, err := tx.ExecBatch(ctx, []*Query{NewQuery("DELETE FROM `a`")}); err != nil {
+				return err
+			}
+			return NewExecutor(tx).WithTx(ctx, nil, func(tx *Tx) error {
+				_, err := tx.Exec(ctx, tx.New().Delete("b"))
+				return err
+			})
+		})
+	})
+	if err != nil {
+		t.Fatalf("WithTx() error: %s", err)
+	}
+
+	want := []string{
+		"BEGIN",
+		"SAVEPOINT `sp_1`",
+		"SAVEPOINT `sp_2`",
+		"DELETE FROM `a`",
+		"RELEASE SAVEPOINT `sp_2`",
+		"SAVEPOINT `sp_2`",
+		"DELETE FROM `b`",
+		"RELEASE SAVEPOINT `sp_2`",
+		"RELEASE SAVEPOINT `sp_1`",
+		"COMMIT",
+	}
+	if got := f.statements(); !reflect.DeepEqual(got, want) {
+		t.Errorf("statements = %#v, want %#v", got, want)
+	}
+}
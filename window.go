@@ -0,0 +1,165 @@
+// Package builder provides a fluent SQL query builder with support for multiple SQL dialects.
+package builder
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Frame bounds of a window, used with Window.Rows and Window.Range.
+// Use Preceding and Following for bounds at a given offset from the current row.
+const (
+	// UnboundedPreceding is the first row of the partition.
+	UnboundedPreceding = "UNBOUNDED PRECEDING"
+	// UnboundedFollowing is the last row of the partition.
+	UnboundedFollowing = "UNBOUNDED FOLLOWING"
+	// CurrentRow is the current row, or its peers in RANGE mode.
+	CurrentRow = "CURRENT ROW"
+)
+
+// Preceding returns the frame bound n rows (or values in RANGE mode) before the current row.
+func Preceding(n int) string {
+	return strconv.Itoa(n) + " PRECEDING"
+}
+
+// Following returns the frame bound n rows (or values in RANGE mode) after the current row.
+func Following(n int) string {
+	return strconv.Itoa(n) + " FOLLOWING"
+}
+
+// Window represents the window of a window function call (see Expr.Over) or of a
+// named WINDOW clause (see Builder.Window), made of an optional partitioning,
+// ordering and frame. Its fields are escaped by the Builder rendering it.
+//
+// Example usage:
+//
+//	// Creates: SUM(amount) OVER (PARTITION BY `user_id` ORDER BY `created_at` ASC
+//	//          ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW) AS `running_total`
+//	w := builder.NewWindow().PartitionBy("user_id").OrderBy(builder.Asc("created_at")).
+//		Rows(builder.UnboundedPreceding, builder.CurrentRow)
+//	b.SelectExpr(builder.NewExpr("SUM(amount)").Over(w).As("running_total"))
+type Window struct {
+	// name is a window defined by Builder.Window that this window refers to or extends
+	name string
+	// partitionBy holds the fields of the PARTITION BY list
+	partitionBy []string
+	// orderBy holds the ordering conditions created by Asc, Desc, AscExpr or DescExpr
+	orderBy []*Condition
+	// frame is the frame clause, e.g. "ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW"
+	frame string
+}
+
+// NewWindow creates a new empty window, rendered as "OVER ()" which spans all the rows.
+func NewWindow() *Window {
+	return &Window{}
+}
+
+// NamedWindow creates a window referring to the window with the given name, defined by
+// Builder.Window. It is rendered as "OVER name", or as "OVER (name ...)" when it is
+// extended with an ordering or a frame.
+func NamedWindow(name string) *Window {
+	return &Window{name: name}
+}
+
+// PartitionBy sets the fields of the PARTITION BY list of the window,
+// which may be qualified.
+// It returns the Window instance for method chaining.
+func (w *Window) PartitionBy(fields ...string) *Window {
+	w.partitionBy = fields
+	return w
+}
+
+// OrderBy sets the ordering of the window, using conditions created by Asc, Desc,
+// AscExpr or DescExpr as in Builder.OrderBy.
+// It returns the Window instance for method chaining.
+func (w *Window) OrderBy(conds ...*Condition) *Window {
+	w.orderBy = conds
+	return w
+}
+
+// Rows sets a ROWS frame from start to end, which are frame bounds such as
+// UnboundedPreceding, CurrentRow or Preceding(3). If end is empty, the frame
+// is rendered as "ROWS start", ending at the current row.
+// It returns the Window instance for method chaining.
+func (w *Window) Rows(start, end string) *Window {
+	w.frame = frame("ROWS", start, end)
+	return w
+}
+
+// Range sets a RANGE frame from start to end like Rows, where offsets are
+// differences of the ordering value rather than numbers of rows.
+// It returns the Window instance for method chaining.
+func (w *Window) Range(start, end string) *Window {
+	w.frame = frame("RANGE", start, end)
+	return w
+}
+
+// frame renders a frame clause of the given mode.
+func frame(mode, start, end string) string {
+	if end == "" {
+		return mode + " " + start
+	}
+	return mode + " BETWEEN " + start + " AND " + end
+}
+
+// Window adds a named window to the WINDOW clause of a SELECT query, which window
+// functions can then refer to with NamedWindow. Calling Window again adds more windows.
+// It returns the Builder instance for method chaining.
+//
+// Example:
+//
+//	b.SelectExpr("name", builder.NewExpr("RANK()").Over(builder.NamedWindow("w")).As("rank")).
+//		From("employees").
+//		Window("w", builder.NewWindow().PartitionBy("dept").OrderBy(builder.Desc("salary")))
+//	// Generates: SELECT `name`, RANK() OVER `w` AS `rank` FROM `employees`
+//	//            WINDOW `w` AS (PARTITION BY `dept` ORDER BY `salary` DESC)
+func (b *Builder) Window(name string, w *Window) *Builder {
+	if w == nil {
+		w = NewWindow()
+	}
+	spec, args := b.buildWindow(w)
+	c := b.clause(WindowClause)
+	if c.sql == "" {
+		c.keyword = " WINDOW "
+	} else {
+		c.sql += ", "
+	}
+	c.sql += b.Escape(name) + " AS (" + spec + ")"
+	c.args = append(c.args, args...)
+	return b
+}
+
+// buildOver renders the OVER part of a window function call along with its arguments.
+func (b *Builder) buildOver(w *Window) (string, []interface{}) {
+	if w.name != "" && len(w.partitionBy) <= 0 && len(w.orderBy) <= 0 && w.frame == "" {
+		return "OVER " + b.Escape(w.name), nil
+	}
+	spec, args := b.buildWindow(w)
+	return "OVER (" + spec + ")", args
+}
+
+// buildWindow renders the specification of a window along with the arguments of its ordering.
+func (b *Builder) buildWindow(w *Window) (string, []interface{}) {
+	var (
+		parts []string
+		args  []interface{}
+	)
+	if w.name != "" {
+		parts = append(parts, b.Escape(w.name))
+	}
+	if len(w.partitionBy) > 0 {
+		escaped := make([]string, len(w.partitionBy))
+		for i, field := range w.partitionBy {
+			escaped[i] = b.Escape(field)
+		}
+		parts = append(parts, "PARTITION BY "+strings.Join(escaped, ", "))
+	}
+	if order, orderArgs := b.buildOrderBy(w.orderBy); order != "" {
+		parts = append(parts, "ORDER BY "+order)
+		args = orderArgs
+	}
+	if w.frame != "" {
+		parts = append(parts, w.frame)
+	}
+	return strings.Join(parts, " "), args
+}
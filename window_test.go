@@ -0,0 +1,98 @@
+package builder
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWindow(t *testing.T) {
+	tests := []struct {
+		name     string
+		d        Dialector
+		build    func(b *Builder) *Builder
+		want     string
+		wantArgs []interface{}
+	}{
+		{
+			name: "row_number",
+			d:    mysqlDialector,
+			build: func(b *Builder) *Builder {
+				w := NewWindow().PartitionBy("dept").OrderBy(Desc("salary"), Asc("e.id"))
+				return b.SelectExpr("name", NewExpr("ROW_NUMBER()").Over(w).As("rank")).From("employees e")
+			},
+			want:     "SELECT `name`, ROW_NUMBER() OVER (PARTITION BY `dept` ORDER BY `salary` DESC, `e`.`id` ASC) AS `rank` FROM `employees` AS `e`",
+			wantArgs: []interface{}{},
+		},
+		{
+			name: "empty_window",
+			d:    sqliteDialector,
+			build: func(b *Builder) *Builder {
+				return b.SelectExpr("id", NewExpr("COUNT(*)").Over(NewWindow()).As("total")).From("users")
+			},
+			want:     `SELECT "id", COUNT(*) OVER () AS "total" FROM "users"`,
+			wantArgs: []interface{}{},
+		},
+		{
+			name: "rows_frame",
+			d:    postgresDialector,
+			build: func(b *Builder) *Builder {
+				w := NewWindow().PartitionBy("user_id").OrderBy(Asc("created_at")).Rows(UnboundedPreceding, CurrentRow)
+				return b.SelectExpr("id", NewExpr("SUM(amount)").Over(w).As("running_total")).
+					From("orders").Where(Eq("status", "paid"))
+			},
+			want:     `SELECT "id", SUM(amount) OVER (PARTITION BY "user_id" ORDER BY "created_at" ASC ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW) AS "running_total" FROM "orders" WHERE "status" = $1`,
+			wantArgs: []interface{}{"paid"},
+		},
+		{
+			name: "range_frame",
+			d:    postgresDialector,
+			build: func(b *Builder) *Builder {
+				avg := NewExpr("AVG(price)").Over(NewWindow().OrderBy(Asc("day")).Range(Preceding(7), Following(0)))
+				start := NewExpr("MIN(price)").Over(NewWindow().OrderBy(Asc("day")).Rows(Preceding(2), ""))
+				return b.SelectExpr("day", avg.As("avg_price"), start.As("min_price")).From("prices")
+			},
+			want:     `SELECT "day", AVG(price) OVER (ORDER BY "day" ASC RANGE BETWEEN 7 PRECEDING AND 0 FOLLOWING) AS "avg_price", MIN(price) OVER (ORDER BY "day" ASC ROWS 2 PRECEDING) AS "min_price" FROM "prices"`,
+			wantArgs: []interface{}{},
+		},
+		{
+			name: "named_windows",
+			d:    mysqlDialector,
+			build: func(b *Builder) *Builder {
+				return b.SelectExpr("name",
+					NewExpr("RANK()").Over(NamedWindow("w")).As("rank"),
+					NewExpr("SUM(salary)").Over(NamedWindow("w").Rows(UnboundedPreceding, CurrentRow)).As("cumulative"),
+				).From("employees").Where(Gt("salary", 1000)).
+					Window("w", NewWindow().PartitionBy("dept").OrderBy(Desc("salary"))).
+					Window("all", nil)
+			},
+			want:     "SELECT `name`, RANK() OVER `w` AS `rank`, SUM(salary) OVER (`w` ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW) AS `cumulative` FROM `employees` WHERE `salary` > ? WINDOW `w` AS (PARTITION BY `dept` ORDER BY `salary` DESC), `all` AS ()",
+			wantArgs: []interface{}{1000},
+		},
+		{
+			name: "order_by_window",
+			d:    postgresDialector,
+			build: func(b *Builder) *Builder {
+				w := NewWindow().PartitionBy("team_id").OrderBy(DescExpr(NewExpr("score * ?", 2)))
+				return b.Select("id", "team_id").From("players").Where(Eq("active", true)).
+					OrderBy(AscExpr(NewExpr("DENSE_RANK()").Over(w)), Asc("id")).
+					Window("t", NewWindow().OrderBy(AscExpr(NewExpr("level + ?", 1))))
+			},
+			want:     `SELECT "id", "team_id" FROM "players" WHERE "active" = $1 WINDOW "t" AS (ORDER BY level + $2 ASC) ORDER BY DENSE_RANK() OVER (PARTITION BY "team_id" ORDER BY score * $3 DESC) ASC, "id" ASC`,
+			wantArgs: []interface{}{true, 1, 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := tt.build(New().SetDialector(tt.d)).Build()
+			if err != nil {
+				t.Errorf("error: %s", err)
+			}
+			if q.Query != tt.want {
+				t.Errorf("\ngot:\n%s\nwant:\n%s\n", q.Query, tt.want)
+			}
+			if !reflect.DeepEqual(tt.wantArgs, q.Args) {
+				t.Errorf("\ngotArgs:\n%#v\nwantArgs:\n%#v\n", q.Args, tt.wantArgs)
+			}
+		})
+	}
+}